@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"oracle/consumer"
+	dbx "oracle/db"
+)
+
+// 서버 대신 운영 명령을 실행 (인자가 있을 때만)
+//
+//	oracle explain <turn_id> <address>
+//	oracle verify-chain
+//	oracle migrate up [version] | down [steps] | status
+func runCLI(database *sql.DB, args []string) int {
+	switch args[0] {
+	case "explain":
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "usage: oracle explain <turn_id> <address>")
+			return 2
+		}
+		turnID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid turn_id:", args[1])
+			return 2
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		exp, err := consumer.ExplainSelection(ctx, database, turnID, args[2])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "explain:", err)
+			return 1
+		}
+		fmt.Print(exp.Text())
+		return 0
+	case "verify-chain":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		rep, err := dbx.VerifyTurnChain(ctx, database)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "verify-chain:", err)
+			return 1
+		}
+		for _, b := range rep.Breaks {
+			fmt.Printf("BREAK seq=%d turn=%s kind=%s %s\n", b.Seq, b.TurnID, b.Kind, b.Detail)
+		}
+		fmt.Printf("checked=%d head_seq=%d head_turn=%s head_hash=%s breaks=%d\n",
+			rep.Checked, rep.Head.Seq, rep.Head.TurnID, rep.Head.ContentHash, len(rep.Breaks))
+		if len(rep.Breaks) > 0 {
+			return 1
+		}
+		return 0
+	case "migrate":
+		return runMigrate(database, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q (commands: explain, verify-chain, migrate)\n", args[0])
+		return 2
+	}
+}
+
+func runMigrate(database *sql.DB, args []string) int {
+	usage := "usage: oracle migrate up [version] | down [steps] | status"
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	n := 0
+	if len(args) == 2 {
+		v, err := strconv.Atoi(args[1])
+		if err != nil || v <= 0 {
+			fmt.Fprintln(os.Stderr, usage)
+			return 2
+		}
+		n = v
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	switch args[0] {
+	case "up":
+		applied, err := dbx.MigrateUp(ctx, database, n)
+		for _, m := range applied {
+			fmt.Printf("applied  %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "migrate up:", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return 0
+	case "down":
+		if n == 0 {
+			n = 1
+		}
+		reverted, err := dbx.MigrateDown(ctx, database, n)
+		for _, m := range reverted {
+			fmt.Printf("reverted %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "migrate down:", err)
+			return 1
+		}
+		return 0
+	case "status":
+		states, err := dbx.MigrationStatus(ctx, database)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "migrate status:", err)
+			return 1
+		}
+		for _, st := range states {
+			mark := "pending"
+			if st.Applied {
+				mark = "applied " + st.AppliedAt.Format(time.RFC3339)
+			}
+			if st.Modified {
+				mark += " (modified)"
+			}
+			if st.Unknown {
+				mark += " (unknown to this binary)"
+			}
+			fmt.Printf("%03d_%-28s %s\n", st.Version, st.Name, mark)
+		}
+		if err := dbx.CheckSchemaVersion(ctx, database); err != nil {
+			fmt.Println("status:", err)
+			return 1
+		}
+		fmt.Println("status: up to date")
+		return 0
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+}
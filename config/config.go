@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 var (
 	// ------------------ Kafka ------------------
 	KafkaBrokers = []string{"Kafka00Service:9092", "Kafka01Service:9092", "Kafka02Service:9092"}
@@ -11,7 +13,9 @@ var (
 	TopicRequestVMemberReward     = "request-vote-member-topic" // 풀노드 -> 오라클 (서명자 보상 결과 전송)
 	TopicTxHash                   = "tx-hash-topic"
 	TopicRequestTxHash            = "request-tx-hash-topic"
-	TopicContributors             = "send-contributors" // 풀노드 -> 기여자 리스트 전송
+	TopicContributors             = "send-contributors"  // 풀노드 -> 기여자 리스트 전송
+	TopicGovProposal              = "gov-proposal-topic" // 풀노드 -> 파라미터 변경 제안 (서명)
+	TopicGovVote                  = "gov-vote-topic"     // 풀노드 -> 제안 찬반 (서명)
 
 	// Kafka Topic Producer
 	TopicDeviceIdToAddressProducer = "device-address-topic"
@@ -22,6 +26,7 @@ var (
 	TopicResultTxhashProducer      = "result-tx-hash-topic"      // 오라클 -> 라이트 노드
 	TopicRECPrice                  = "rec-price-topic"           // 오라클 -> 라이트 노드
 	TopicBlockCreator              = "block-creator"             // 블록 생성사 선출 결과
+	TopicTurnChainHead             = "turn-chain-head"           // 턴 결과 해시 체인 헤드 (주기 공개)
 
 	// Kafka Group
 	GroupVote              = "vote-member-group"
@@ -83,4 +88,102 @@ var (
 
 	EnablePCap = true
 	Pcap       = 0.35
+
+	// ---------------- 에너지 항(x_i) 변환 ----------------
+	EnergyTransform         = "linear" // "linear" | "capacity" | "sqrt" | "log" | "clip"
+	EnergyDefaultCapacityKw = 3.0      // capacity 모드: plant_capacity 미등록 주소의 기본 설비 용량(kW, 가정용 루프탑)
+	EnergyClipKwh           = 0.0      // clip 모드: 고정 상한(kWh). 0이면 분위수 사용
+	EnergyClipQuantile      = 0.9      // clip 모드: 이번 턴 양수 에너지의 분위수 상한
+
+	// ---------------- 엔티티 그룹핑 (Sybil 방지) ----------------
+	EntityGroupingOn = true                                   // P-cap/공정성 한도를 주소가 아닌 엔티티 단위로 적용
+	EntityGroupKeys  = []string{"public_key", "device_owner"} // 같은 값이면 같은 엔티티 (+ entity_link 수동 연결)
+
+	// ---------------- 선발 후보 적격성 규칙 ----------------
+	EligRequireRegistered = false            // userData에 등록된 주소만 후보 (기본 꺼짐: 미등록 기여자도 기존처럼 후보)
+	EligMinAccountAge     = time.Duration(0) // 최초 등록 후 최소 경과 시간 (0 = 미적용)
+	EligMinEnergyKwh      = 0.0              // 기여자 최소 에너지(kWh) (0 = 미적용)
+	EligIncludeVoteOnly   = true             // vote_counter 점수만 있는 주소도 후보로 포함
+	EligUseDenylist       = true             // selection_denylist 적용
+	EligUseMissPenalty    = true             // 연속 미참여 패널티(reward_credit.ineligible_until) 적용
+
+	// ---------------- 다중 풀노드 기여자 보고 quorum ----------------
+	ContributorQuorum       = 1               // 같은 턴 보고를 모을 풀노드 수 (1 = quorum 없이 즉시 선발)
+	QuorumTimeout           = 5 * time.Second // quorum 대기 최대 시간
+	QuorumMinOnTimeout      = 1               // timeout 시 선발에 필요한 최소 보고 수
+	QuorumRequireRegistered = true            // quorum 모드(ContributorQuorum > 1)에서 fullnode_registry(active) 풀노드 보고만 집계
+
+	// ---------------- 권역 다양성 선발 ----------------
+	GeoDiversityMode  = "off" // "off" | "cap" | "stratify"
+	GeoWinWindowN     = 30    // cap: 최근 N턴 승리 기준
+	GeoRegionCapShare = 0.5   // cap: 창 내 권역 승리 비중 상한
+	GeoCapFactor      = 0.0   // cap: 상한 도달 권역 후보 가중치 배율 (0 = 이번 턴 제외)
+
+	// ---------------- 신규/저점수 참여자 확률 하한 ----------------
+	ProbFloorMode   = "off" // "off" | "absolute" | "uniform" (P-cap 전 적격 후보에 적용)
+	ProbFloorValue  = 0.1   // absolute: 최소 P_i, uniform: 균등 확률(1/n) 대비 비율
+	ProbFloorBudget = 0.2   // 하한 보정으로 옮길 수 있는 총 확률 상한
+
+	// ---------------- 인메모리 후보/점수 인덱스 ----------------
+	// 인덱스 스냅샷 ID("index:<version>")는 프로세스 내 카운터라 재시작 후 DB에서 재현할 수 없으므로 기본 OFF
+	// (OFF면 턴마다 REPEATABLE READ 스냅샷을 읽고 txid 스냅샷을 기록)
+	CandidateIndexOn        = false           // 턴마다 vote_counter 전체 스캔 대신 인메모리 인덱스 사용
+	CandidateIndexReconcile = 5 * time.Minute // 주기적 전체 재동기화 간격 (NOTIFY 유실 보정)
+
+	// ---------------- 턴 결과 해시 체인 ----------------
+	TurnChainPublishInterval = time.Minute // 체인 헤드 Kafka 공개 주기 (0 = 비활성)
+
+	// ---------------- 온체인 앵커링 (Merkle root) ----------------
+	AnchorOn        = false            // 주기적 앵커링 작업 ON/OFF
+	AnchorInterval  = 10 * time.Minute // epoch 주기
+	AnchorMaxLeaves = 10000            // epoch 당 최대 잎 수
+	AnchorRPCHost   = "192.168.0.19"   // CometBFT RPC (broadcast_tx_sync)
+	AnchorRPCPort   = 26657
+
+	// ---------------- 파라미터 거버넌스 ----------------
+	GovQuorumFrac         = 2.0 / 3.0 // 승인에 필요한 active 풀노드 찬성 비율
+	GovMinActivationDelay = 100       // 제안 시점 마지막 턴 대비 최소 적용 지연(턴)
+
+	// ---------------- 보상 참여율 분모 N ----------------
+	RewardNSource      = "eligible" // "eligible" (요청 시점 적격 검증자 집합) | "config" (LightNodeUser, 과거 방식)
+	RewardNActiveDays  = 30         // eligible: 최근 X일 내 보상 참여(또는 신규 등록)한 주소만 (0 = 등록 주소 전체)
+	RewardNPerFullnode = false      // eligible: 요청 풀노드에 등록된(userData.node_id) 주소만
+
+	// ---------------- 검증자 보상 곡선 ----------------
+	RewardBonusCurve    = "linear"       // "linear" | "power" | "logistic" | "piecewise" (r_eff → 보너스 비율)
+	RewardBonusGamma    = 1.0            // power: r_eff^γ
+	RewardLogisticK     = 10.0           // logistic: 기울기
+	RewardLogisticMid   = 0.5            // logistic: 중심 r_eff
+	RewardPiecewise     = [][2]float64{} // piecewise: (r_eff, 비율) 점 목록, 사이는 선형 보간
+	RewardStreakStep    = 0.0            // 연속 참여 1라운드당 추가 배율 (0 = 미적용)
+	RewardStreakCap     = 0.0            // 연속 참여 추가 배율 상한 (예: 0.2 => 최대 x1.2)
+	RewardStreakStartAt = 2              // 몇 번째 연속 참여부터 배율 적용
+
+	// ---------------- 보상 발행 스케줄 (epoch 예산) ----------------
+	EmissionOn            = false                                       // epoch 예산 적용 ON/OFF (OFF = 무제한)
+	EmissionGenesis       = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) // epoch 0 시작 시각
+	EmissionEpoch         = 24 * time.Hour                              // epoch 길이
+	EmissionBudget0       = 10000.0                                     // epoch 0 예산 (보상 합계)
+	EmissionSchedule      = "constant"                                  // "constant" | "decay" | "halving"
+	EmissionDecayRate     = 0.001                                       // decay: epoch마다 예산 × (1 - rate)
+	EmissionHalvingEpochs = 365                                         // halving: 이 epoch 수마다 예산 절반
+
+	// ---------------- 검증자 미참여 패널티 ----------------
+	PenaltyMissDeduction  = 0.0 // 기대 검증자 미참여 1회당 vote_counter 차감 (0 = 차감 없음, 0 미만으로는 내려가지 않음)
+	PenaltyBanAfterMisses = 0   // 이 횟수 연속 미참여 시 블록 생성자 선발에서 일시 제외 (0 = 미적용)
+	PenaltyBanHours       = 24  // 선발 제외 기간(시간)
+
+	// ---------------- 권역별 R0 ----------------
+	RewardR0Regional    = false // 검증자 보상에 등록 권역(user_region)의 R0 적용 (false = 전국 R0 단일)
+	RewardR0MinStations = 3     // 권역 관측지점이 이보다 적으면 전국 R0로 대체
+
+	// ---------------- 보상 결과 재송신 ----------------
+	RewardPublishRetryInterval = time.Minute      // published_at이 빈 라운드 재송신 주기 (0 = 비활성)
+	RewardPublishRetryMinAge   = 30 * time.Second // 생성 직후 라운드는 consumer가 송신 중일 수 있으므로 제외
+	RewardPublishRetryMaxAge   = 24 * time.Hour   // 이보다 오래된 미송신 라운드는 재송신하지 않음 (수동 확인)
+
+	// ---------------- 관리자 API ----------------
+	AdminAPIToken = "" // 환경변수 ORACLE_ADMIN_TOKEN 우선, 비어 있으면 관리자 API 전부 거부
+	// 운영자(읽기 전용) 토큰: 선발 what-if/explain만 허용, 레지스트리 변경 API는 거부
+	OperatorAPIToken = "" // 환경변수 ORACLE_OPERATOR_TOKEN 우선
 )
@@ -0,0 +1,154 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	dbx "oracle/db"
+)
+
+// AccountRewardsHandler : 주소별 보상 내역 (GET /accounts/{address}/rewards?limit=&before=<round_id>[&format=csv])
+// - JSON: 요약(누적 지급/차감, 현재 vote_counter 점수) + 최신 라운드부터 페이지
+// - CSV : 전체 내역을 오래된 순으로 내보내기 (페이지 인자 무시)
+func AccountRewardsHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"status":  "fail",
+				"message": "Method not allowed",
+			})
+			return
+		}
+		address := strings.TrimSpace(r.PathValue("address"))
+		if address == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"status":  "fail",
+				"message": "address is required",
+			})
+			return
+		}
+		q := r.URL.Query()
+		if strings.EqualFold(q.Get("format"), "csv") {
+			writeAccountRewardsCSV(w, r, db, address)
+			return
+		}
+		limit := 50
+		if v, err := strconv.Atoi(q.Get("limit")); err == nil && v > 0 && v <= 500 {
+			limit = v
+		}
+		var before int64
+		if v := q.Get("before"); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n <= 0 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{
+					"status":  "fail",
+					"message": "before must be a positive round_id",
+				})
+				return
+			}
+			before = n
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+		summary, err := dbx.GetAccountRewardSummary(ctx, db, address)
+		if err != nil {
+			log.Printf("[AccountRewards] summary address=%s: %v", address, err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"status":  "fail",
+				"message": "Failed to load rewards",
+			})
+			return
+		}
+		list, err := dbx.ListAccountRewards(ctx, db, address, before, limit)
+		if err != nil {
+			log.Printf("[AccountRewards] list address=%s: %v", address, err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"status":  "fail",
+				"message": "Failed to load rewards",
+			})
+			return
+		}
+		resp := map[string]any{
+			"status":  "success",
+			"summary": summary,
+			"rewards": list,
+		}
+		// 다음 페이지 커서 (마지막 페이지면 없음)
+		if len(list) == limit {
+			resp["next_before"] = list[len(list)-1].RoundID
+		}
+		writeJSONValue(w, http.StatusOK, resp)
+	}
+}
+
+var accountRewardsCSVHeader = []string{
+	"round_id", "created_at", "fullnode_id", "kind", "n", "N", "rate", "base_reward", "region", "r0", "policy_version",
+	"streak", "multiplier", "reward", "running_total", "reset", "before_count", "after_count",
+	"miss_streak", "ineligible_until", "content_hash",
+}
+
+func writeAccountRewardsCSV(w http.ResponseWriter, r *http.Request, db *sql.DB, address string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	cw := csv.NewWriter(w)
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="rewards_`+csvFileSafe(address)+`.csv"`)
+		return cw.Write(accountRewardsCSVHeader)
+	}
+	err := dbx.ForEachAccountReward(ctx, db, address, func(x dbx.AccountRewardRow) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		until := ""
+		if x.IneligibleUntil != nil {
+			until = x.IneligibleUntil.UTC().Format(time.RFC3339)
+		}
+		return cw.Write([]string{
+			strconv.FormatInt(x.RoundID, 10), x.CreatedAt.UTC().Format(time.RFC3339), x.FullnodeID, x.Kind,
+			strconv.Itoa(x.Participants), strconv.Itoa(x.Total), f(x.Rate), f(x.BaseReward), x.Region, f(x.R0), x.PolicyVersion,
+			strconv.Itoa(x.Streak), f(x.Multiplier), f(x.Reward), f(x.RunningTotal), strconv.FormatBool(x.Reset),
+			f(x.BeforeCount), f(x.AfterCount), strconv.Itoa(x.MissStreak), until, x.ContentHash,
+		})
+	})
+	if err != nil {
+		log.Printf("[AccountRewards] csv address=%s: %v", address, err)
+		// 본문을 쓰기 시작한 뒤면 상태 코드를 바꿀 수 없으므로 잘린 채로 끝낸다
+		if !started {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"status":  "fail",
+				"message": "Failed to export rewards",
+			})
+		}
+		cw.Flush()
+		return
+	}
+	if !started {
+		_ = start() // 내역 없음: 헤더만
+	}
+	cw.Flush()
+}
+
+// 파일명에 쓸 수 없는 문자는 '_'로
+func csvFileSafe(s string) string {
+	return strings.Map(func(c rune) rune {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+			return c
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,83 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	dbx "oracle/db"
+)
+
+// DenylistHandler : 선발 차단 목록 조회(GET) / 추가(POST) / 해제(DELETE ?address=)
+func DenylistHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			list, err := dbx.ListDenylist(ctx, db)
+			if err != nil {
+				log.Printf("[DenylistAdmin] list error: %v", err)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"status":  "fail",
+					"message": "Failed to load denylist",
+				})
+				return
+			}
+			writeJSONValue(w, http.StatusOK, map[string]any{
+				"status":   "success",
+				"denylist": list,
+			})
+
+		case http.MethodPost:
+			var req dbx.DenylistEntry
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Address == "" {
+				writeJSON(w, http.StatusBadRequest, map[string]string{
+					"status":  "fail",
+					"message": "address is required",
+				})
+				return
+			}
+			if err := dbx.UpsertDenylist(ctx, db, req); err != nil {
+				log.Printf("[DenylistAdmin] upsert error: %v", err)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"status":  "fail",
+					"message": "Failed to save denylist entry",
+				})
+				return
+			}
+			log.Printf("[DenylistAdmin] denied address=%s reason=%s", req.Address, req.Reason)
+			writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+
+		case http.MethodDelete:
+			addr := r.URL.Query().Get("address")
+			if addr == "" {
+				writeJSON(w, http.StatusBadRequest, map[string]string{
+					"status":  "fail",
+					"message": "address is required",
+				})
+				return
+			}
+			if err := dbx.DeleteDenylist(ctx, db, addr); err != nil {
+				log.Printf("[DenylistAdmin] delete error: %v", err)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"status":  "fail",
+					"message": "Failed to delete denylist entry",
+				})
+				return
+			}
+			log.Printf("[DenylistAdmin] released address=%s", addr)
+			writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+
+		default:
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"status":  "fail",
+				"message": "Method not allowed",
+			})
+		}
+	}
+}
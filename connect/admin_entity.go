@@ -0,0 +1,122 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"oracle/config"
+	dbx "oracle/db"
+)
+
+// 수동 연결 요청
+type EntityLinkRequest struct {
+	EntityID  string   `json:"entity_id"`
+	Addresses []string `json:"addresses"`
+	Note      string   `json:"note"`
+}
+
+// EntityClustersHandler : 탐지된 엔티티 클러스터 조회(GET) / 수동 연결(POST)
+func EntityClustersHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			groups, err := dbx.ListEntityClusters(ctx, db, config.EntityGroupKeys)
+			if err != nil {
+				log.Printf("[EntityAdmin] cluster query error: %v", err)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"status":  "fail",
+					"message": "Failed to load clusters",
+				})
+				return
+			}
+			writeJSONValue(w, http.StatusOK, map[string]any{
+				"status":   "success",
+				"clusters": groups,
+			})
+
+		case http.MethodPost:
+			var req EntityLinkRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.EntityID == "" || len(req.Addresses) == 0 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{
+					"status":  "fail",
+					"message": "entity_id and addresses are required",
+				})
+				return
+			}
+			if err := dbx.LinkEntityAddresses(ctx, db, req.EntityID, req.Note, req.Addresses); err != nil {
+				log.Printf("[EntityAdmin] link error: %v", err)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"status":  "fail",
+					"message": "Failed to link addresses",
+				})
+				return
+			}
+			log.Printf("[EntityAdmin] linked entity=%s addresses=%v", req.EntityID, req.Addresses)
+			writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+
+		default:
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"status":  "fail",
+				"message": "Method not allowed",
+			})
+		}
+	}
+}
+
+// DeviceOwnerHandler : 디바이스 소유자 레지스트리 조회(GET) / 등록·갱신(POST)
+func DeviceOwnerHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			list, err := dbx.ListDeviceOwners(ctx, db)
+			if err != nil {
+				log.Printf("[EntityAdmin] device owner list error: %v", err)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"status":  "fail",
+					"message": "Failed to load device owners",
+				})
+				return
+			}
+			writeJSONValue(w, http.StatusOK, map[string]any{
+				"status":        "success",
+				"device_owners": list,
+			})
+
+		case http.MethodPost:
+			var req dbx.DeviceOwner
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.DeviceID == "" || req.OwnerID == "" {
+				writeJSON(w, http.StatusBadRequest, map[string]string{
+					"status":  "fail",
+					"message": "device_id and owner_id are required",
+				})
+				return
+			}
+			if err := dbx.UpsertDeviceOwner(ctx, db, req.DeviceID, req.OwnerID); err != nil {
+				log.Printf("[EntityAdmin] device owner upsert error: %v", err)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"status":  "fail",
+					"message": "Failed to save device owner",
+				})
+				return
+			}
+			log.Printf("[EntityAdmin] device=%s owner=%s", req.DeviceID, req.OwnerID)
+			writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+
+		default:
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"status":  "fail",
+				"message": "Method not allowed",
+			})
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"oracle/consumer"
+)
+
+// ExplainHandler : 턴/주소별 선발 확률 분해 (GET ?turn=&address=)
+func ExplainHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"status":  "fail",
+				"message": "Method not allowed",
+			})
+			return
+		}
+		turnID, err := strconv.ParseInt(r.URL.Query().Get("turn"), 10, 64)
+		addr := strings.TrimSpace(r.URL.Query().Get("address"))
+		if err != nil || addr == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"status":  "fail",
+				"message": "turn and address are required",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+		exp, err := consumer.ExplainSelection(ctx, db, turnID, addr)
+		if err != nil {
+			log.Printf("[Explain] turn=%d address=%s: %v", turnID, addr, err)
+			if errors.Is(err, consumer.ErrExplainNotFound) {
+				writeJSON(w, http.StatusNotFound, map[string]string{
+					"status":  "fail",
+					"message": err.Error(),
+				})
+				return
+			}
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"status":  "fail",
+				"message": "Failed to explain selection",
+			})
+			return
+		}
+		writeJSONValue(w, http.StatusOK, map[string]any{
+			"status":      "success",
+			"explanation": exp,
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	dbx "oracle/db"
+)
+
+// FullnodeRegistryHandler : 등록 풀노드 조회(GET) / 등록·갱신(POST)
+func FullnodeRegistryHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			list, err := dbx.ListFullnodes(ctx, db)
+			if err != nil {
+				log.Printf("[FullnodeAdmin] list error: %v", err)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"status":  "fail",
+					"message": "Failed to load fullnodes",
+				})
+				return
+			}
+			writeJSONValue(w, http.StatusOK, map[string]any{
+				"status":    "success",
+				"fullnodes": list,
+			})
+
+		case http.MethodPost:
+			req := dbx.Fullnode{Active: true}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.FullnodeID == "" {
+				writeJSON(w, http.StatusBadRequest, map[string]string{
+					"status":  "fail",
+					"message": "fullnode_id is required",
+				})
+				return
+			}
+			if err := dbx.UpsertFullnode(ctx, db, req); err != nil {
+				log.Printf("[FullnodeAdmin] upsert error: %v", err)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"status":  "fail",
+					"message": "Failed to save fullnode",
+				})
+				return
+			}
+			log.Printf("[FullnodeAdmin] fullnode=%s active=%v", req.FullnodeID, req.Active)
+			writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+
+		default:
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"status":  "fail",
+				"message": "Method not allowed",
+			})
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	dbx "oracle/db"
+)
+
+// PlantCapacityHandler : 발전소 설비 용량 레지스트리 조회(GET) / 등록·갱신(POST)
+func PlantCapacityHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		switch r.Method {
+		case http.MethodGet:
+			plants, err := dbx.ListPlantCapacities(ctx, db)
+			if err != nil {
+				log.Printf("[PlantAdmin] list error: %v", err)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"status":  "fail",
+					"message": "Failed to load plants",
+				})
+				return
+			}
+			writeJSONValue(w, http.StatusOK, map[string]any{
+				"status": "success",
+				"plants": plants,
+			})
+
+		case http.MethodPost:
+			var req dbx.PlantCapacity
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Address == "" || req.CapacityKw <= 0 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{
+					"status":  "fail",
+					"message": "address and positive capacity_kw are required",
+				})
+				return
+			}
+			if err := dbx.UpsertPlantCapacity(ctx, db, req.Address, req.CapacityKw); err != nil {
+				log.Printf("[PlantAdmin] upsert error: %v", err)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"status":  "fail",
+					"message": "Failed to save plant capacity",
+				})
+				return
+			}
+			log.Printf("[PlantAdmin] address=%s capacity_kw=%.3f", req.Address, req.CapacityKw)
+			writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+
+		default:
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"status":  "fail",
+				"message": "Method not allowed",
+			})
+		}
+	}
+}
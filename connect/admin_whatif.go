@@ -0,0 +1,44 @@
+package connect
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"oracle/consumer"
+)
+
+// WhatIfHandler : 기여자 목록 + 파라미터 덮어쓰기로 가상 선발 (POST, 기록 없음)
+func WhatIfHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"status":  "fail",
+				"message": "Method not allowed",
+			})
+			return
+		}
+		var req consumer.WhatIfRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"status":  "fail",
+				"message": "Invalid request body",
+			})
+			return
+		}
+		res, err := consumer.WhatIfSelection(db, req)
+		if err != nil {
+			log.Printf("[WhatIf] selection error: %v", err)
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"status":  "fail",
+				"message": err.Error(),
+			})
+			return
+		}
+		writeJSONValue(w, http.StatusOK, map[string]any{
+			"status": "success",
+			"result": res,
+		})
+	}
+}
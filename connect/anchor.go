@@ -0,0 +1,60 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"oracle/consumer"
+)
+
+// AnchorProofHandler : 턴/보상 항목의 Merkle 포함 증명 (GET ?kind=turn&ref=<turn_id> 또는 ?kind=reward&ref=<round_id>:<address>)
+func AnchorProofHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"status":  "fail",
+				"message": "Method not allowed",
+			})
+			return
+		}
+		kind := strings.TrimSpace(r.URL.Query().Get("kind"))
+		ref := strings.TrimSpace(r.URL.Query().Get("ref"))
+		if kind == "" {
+			kind = "turn"
+		}
+		if ref == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"status":  "fail",
+				"message": "ref is required",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+		proof, found, err := consumer.GetAnchorProof(ctx, db, kind, ref)
+		if err != nil {
+			log.Printf("[AnchorProof] kind=%s ref=%s: %v", kind, ref, err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"status":  "fail",
+				"message": "Failed to build proof",
+			})
+			return
+		}
+		if !found {
+			writeJSON(w, http.StatusNotFound, map[string]string{
+				"status":  "fail",
+				"message": "Not anchored yet",
+			})
+			return
+		}
+		writeJSONValue(w, http.StatusOK, map[string]any{
+			"status": "success",
+			"proof":  proof,
+		})
+	}
+}
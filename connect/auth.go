@@ -0,0 +1,73 @@
+package connect
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"oracle/config"
+)
+
+// 관리자 토큰: 환경변수 우선, 없으면 config 폴백
+func adminToken() string {
+	if t := strings.TrimSpace(os.Getenv("ORACLE_ADMIN_TOKEN")); t != "" {
+		return t
+	}
+	return strings.TrimSpace(config.AdminAPIToken)
+}
+
+// 운영자(읽기 전용) 토큰: 환경변수 우선, 없으면 config 폴백
+func operatorToken() string {
+	if t := strings.TrimSpace(os.Getenv("ORACLE_OPERATOR_TOKEN")); t != "" {
+		return t
+	}
+	return strings.TrimSpace(config.OperatorAPIToken)
+}
+
+// Authorization: Bearer <token> 검사. 토큰이 설정되지 않았으면 항상 거부.
+func bearerMatches(r *http.Request, want string) bool {
+	if want == "" {
+		return false
+	}
+	got := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// RequireAdmin : 관리자 API 보호 래퍼
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !bearerMatches(r, adminToken()) {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{
+				"status":  "fail",
+				"message": "Unauthorized",
+			})
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RequireOperator : 읽기 전용 운영자 API 보호 래퍼 (운영자 토큰 또는 관리자 토큰)
+// - 운영자에게 관리자 토큰(레지스트리/차단 목록 변경 권한)을 나눠주지 않기 위한 별도 범위
+// - 상태를 바꾸지 않는 조회/시뮬레이션 핸들러에만 사용
+func RequireOperator(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !bearerMatches(r, operatorToken()) && !bearerMatches(r, adminToken()) {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{
+				"status":  "fail",
+				"message": "Unauthorized",
+			})
+			return
+		}
+		next(w, r)
+	}
+}
+
+// 임의 구조체 JSON 응답 (writeJSON은 map[string]string 전용)
+func writeJSONValue(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
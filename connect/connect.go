@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	dbx "oracle/db"
 	"oracle/types"
 
 	"github.com/segmentio/kafka-go"
@@ -41,5 +42,12 @@ func ConnectHandler(db *sql.DB, writer *kafka.Writer) http.HandlerFunc {
 			http.Error(w, `{"status":"fail","message":"Failed to insert user data"}`, http.StatusInternalServerError)
 			return
 		}
+
+		// 4. 설치 위치 저장 (권역 다양성 선발용, 선택 항목)
+		if req.Location != "" && req.Address != "" {
+			if err := dbx.UpsertUserLocation(r.Context(), db, req.Address, req.Location); err != nil {
+				log.Printf("[ConnectHandler] location save error: %v", err)
+			}
+		}
 	}
 }
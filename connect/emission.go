@@ -0,0 +1,40 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"oracle/consumer"
+)
+
+// EmissionHandler : 현재 epoch 보상 예산/사용량/잔여 (GET)
+func EmissionHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"status":  "fail",
+				"message": "Method not allowed",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+		st, err := consumer.EmissionStatusAt(ctx, db, time.Now().UTC())
+		if err != nil {
+			log.Printf("[Emission] status error: %v", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"status":  "fail",
+				"message": "Failed to load emission status",
+			})
+			return
+		}
+		writeJSONValue(w, http.StatusOK, map[string]any{
+			"status":   "success",
+			"emission": st,
+		})
+	}
+}
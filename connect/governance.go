@@ -0,0 +1,45 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"oracle/consumer"
+)
+
+// GovernanceHistoryHandler : 파라미터 변경 제안/투표 이력 (GET ?limit=)
+func GovernanceHistoryHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"status":  "fail",
+				"message": "Method not allowed",
+			})
+			return
+		}
+		limit := 100
+		if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 && v <= 1000 {
+			limit = v
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+		list, err := consumer.GovernanceHistory(ctx, db, limit)
+		if err != nil {
+			log.Printf("[Governance] history error: %v", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"status":  "fail",
+				"message": "Failed to load governance history",
+			})
+			return
+		}
+		writeJSONValue(w, http.StatusOK, map[string]any{
+			"status":    "success",
+			"proposals": list,
+		})
+	}
+}
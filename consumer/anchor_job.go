@@ -0,0 +1,199 @@
+// oracle/consumer/anchor_job.go
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"oracle/config"
+	dbx "oracle/db"
+)
+
+// broadcast_tx_sync 응답 (CometBFT JSON-RPC)
+type tmBroadcastRPC struct {
+	Result struct {
+		Code int    `json:"code"`
+		Log  string `json:"log"`
+		Hash string `json:"hash"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// 앵커 tx 본문: 체인 앱이 해석할 수 있는 짧은 JSON
+func anchorTxBytes(epochID int64, root string, leaves int) []byte {
+	b, _ := json.Marshal(map[string]any{
+		"type":        "oracle_anchor",
+		"epoch_id":    epochID,
+		"merkle_root": root,
+		"leaf_count":  leaves,
+	})
+	return b
+}
+
+// BroadcastTxSyncViaRPC : GET /broadcast_tx_sync?tx=0x<hex>
+func BroadcastTxSyncViaRPC(rpcHost string, rpcPort int, tx []byte) (hash string, code int, log string, err error) {
+	url := fmt.Sprintf("http://%s:%d/broadcast_tx_sync?tx=0x%s", rpcHost, rpcPort, strings.ToUpper(hex.EncodeToString(tx)))
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("http get: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return "", 0, "", fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+	}
+	var rpc tmBroadcastRPC
+	if err := json.Unmarshal(body, &rpc); err != nil {
+		return "", 0, "", fmt.Errorf("rpc unmarshal: %w", err)
+	}
+	if rpc.Error != nil {
+		return "", 0, "", fmt.Errorf("rpc error %d: %s %s", rpc.Error.Code, rpc.Error.Message, rpc.Error.Data)
+	}
+	return rpc.Result.Hash, rpc.Result.Code, rpc.Result.Log, nil
+}
+
+// 앵커링 대상 잎 수집: 체인 턴 결과 → 보상 적립 (합계 limit개)
+func collectAnchorLeaves(ctx context.Context, db *sql.DB, limit int) ([]dbx.AnchorLeaf, error) {
+	leaves, err := dbx.FetchUnanchoredTurnLeaves(ctx, db, limit)
+	if err != nil {
+		return nil, err
+	}
+	rewards, err := dbx.FetchUnanchoredRewardLeaves(ctx, db, limit-len(leaves))
+	if err != nil {
+		return nil, err
+	}
+	leaves = append(leaves, rewards...)
+	for i := range leaves {
+		leaves[i].LeafHash = merkleLeafHash(leaves[i].Kind, leaves[i].Ref, leaves[i].Payload)
+	}
+	return leaves, nil
+}
+
+func submitAnchorEpoch(ctx context.Context, db *sql.DB, e dbx.AnchorEpoch) {
+	hash, code, rpcLog, err := BroadcastTxSyncViaRPC(config.AnchorRPCHost, config.AnchorRPCPort,
+		anchorTxBytes(e.EpochID, e.MerkleRoot, e.LeafCount))
+	status := "submitted"
+	if err != nil {
+		status, rpcLog = "failed", err.Error()
+	} else if code != 0 {
+		status = "failed"
+	}
+	if err := dbx.MarkAnchorEpoch(ctx, db, e.EpochID, status, hash, code, rpcLog); err != nil {
+		fmt.Printf("[Anchor] epoch=%d status update failed: %v\n", e.EpochID, err)
+		return
+	}
+	fmt.Printf("[Anchor] epoch=%d root=%s leaves=%d status=%s tx=%s code=%d\n",
+		e.EpochID, e.MerkleRoot, e.LeafCount, status, hash, code)
+}
+
+// 1회 실행: 실패한 epoch 재제출 후 새 epoch 생성/제출
+func runAnchorOnce(ctx context.Context, db *sql.DB) error {
+	pending, err := dbx.ListUnsubmittedAnchorEpochs(ctx, db)
+	if err != nil {
+		return err
+	}
+	for _, e := range pending {
+		submitAnchorEpoch(ctx, db, e)
+	}
+
+	leaves, err := collectAnchorLeaves(ctx, db, config.AnchorMaxLeaves)
+	if err != nil {
+		return err
+	}
+	if len(leaves) == 0 {
+		return nil
+	}
+	hashes := make([]string, len(leaves))
+	for i, l := range leaves {
+		hashes[i] = l.LeafHash
+	}
+	root, _, err := merkleRootAndProof(hashes, -1)
+	if err != nil {
+		return err
+	}
+	epochID, err := dbx.InsertAnchorEpoch(ctx, db, root, leaves)
+	if err != nil {
+		return err
+	}
+	submitAnchorEpoch(ctx, db, dbx.AnchorEpoch{EpochID: epochID, MerkleRoot: root, LeafCount: len(leaves)})
+	return nil
+}
+
+// StartAnchorJob : AnchorInterval마다 미앵커링 잎으로 Merkle root를 만들어 broadcast_tx_sync
+func StartAnchorJob(db *sql.DB) {
+	every := config.AnchorInterval
+	if every <= 0 {
+		every = 10 * time.Minute
+	}
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for range t.C {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		if err := runAnchorOnce(ctx, db); err != nil {
+			fmt.Println("[Anchor] run failed:", err)
+		}
+		cancel()
+	}
+}
+
+// 잎 포함 증명 (API 응답)
+type AnchorProof struct {
+	Kind         string          `json:"kind"`
+	Ref          string          `json:"ref"`
+	LeafHash     string          `json:"leaf_hash"`      // 앵커링 당시 저장한 잎 해시
+	LiveLeafHash string          `json:"live_leaf_hash"` // 지금 행(content_hash)에서 다시 만든 잎 해시
+	RowIntact    bool            `json:"row_intact"`     // 행 내용으로 재계산한 content_hash == 저장값
+	Index        int             `json:"leaf_index"`
+	Proof        []MerkleStep    `json:"proof"`
+	Epoch        dbx.AnchorEpoch `json:"epoch"`
+	Verified     bool            `json:"verified"` // 현재 행 → 잎 → 저장된 root까지 모두 일치
+}
+
+// GetAnchorProof : kind/ref 잎의 Merkle 포함 증명 (없으면 found=false)
+//   - 증명은 anchor_leaf의 잎 해시로 만들지만, Verified는 출처 행을 다시 읽어 만든 잎이 그 해시와 같을 때만 true
+//     (앵커링 이후 turn_result/reward_credit이 바뀌었으면 false)
+func GetAnchorProof(ctx context.Context, db *sql.DB, kind, ref string) (AnchorProof, bool, error) {
+	out := AnchorProof{Kind: kind, Ref: ref}
+	epochID, index, hashes, found, err := dbx.GetAnchorLeafEpoch(ctx, db, kind, ref)
+	if err != nil || !found {
+		return out, false, err
+	}
+	if index < 0 || index >= len(hashes) {
+		return out, false, fmt.Errorf("leaf index %d out of range (%d leaves)", index, len(hashes))
+	}
+	epoch, err := dbx.GetAnchorEpoch(ctx, db, epochID)
+	if err != nil {
+		return out, false, err
+	}
+	root, proof, err := merkleRootAndProof(hashes, index)
+	if err != nil {
+		return out, false, err
+	}
+	out.LeafHash, out.Index, out.Proof, out.Epoch = hashes[index], index, proof, epoch
+
+	src, live, err := dbx.GetAnchorLeafSource(ctx, db, kind, ref)
+	if err != nil {
+		return out, false, err
+	}
+	if live {
+		out.LiveLeafHash = merkleLeafHash(kind, ref, src.Payload)
+		out.RowIntact = src.Intact
+	}
+	out.Verified = live && src.Intact && out.LiveLeafHash == out.LeafHash &&
+		root == epoch.MerkleRoot && verifyMerkleProof(out.LiveLeafHash, proof, epoch.MerkleRoot)
+	return out, true, nil
+}
@@ -0,0 +1,234 @@
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"oracle/config"
+	dbx "oracle/db"
+)
+
+// 가짜 CometBFT RPC: /broadcast_tx_sync 요청의 tx를 기록하고 reply가 돌려준 응답을 쓴다
+type fakeRPC struct {
+	mu    sync.Mutex
+	txs   [][]byte
+	reply func(w http.ResponseWriter)
+}
+
+func (f *fakeRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/broadcast_tx_sync" {
+		http.NotFound(w, r)
+		return
+	}
+	tx, err := hex.DecodeString(strings.TrimPrefix(r.URL.Query().Get("tx"), "0x"))
+	if err != nil {
+		http.Error(w, "bad tx", http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	f.txs = append(f.txs, tx)
+	reply := f.reply
+	f.mu.Unlock()
+	reply(w)
+}
+
+func (f *fakeRPC) setReply(fn func(w http.ResponseWriter)) {
+	f.mu.Lock()
+	f.reply = fn
+	f.mu.Unlock()
+}
+
+func rpcResult(code int, hash, log string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":-1,"result":{"code":` + strconv.Itoa(code) +
+			`,"data":"","log":"` + log + `","codespace":"","hash":"` + hash + `"}}`))
+	}
+}
+
+func startFakeRPC(t *testing.T) (*fakeRPC, string, int) {
+	t.Helper()
+	f := &fakeRPC{reply: rpcResult(0, "TXHASH", "")}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	host, portStr, err := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.Atoi(portStr)
+	return f, host, port
+}
+
+func TestBroadcastTxSyncViaRPC(t *testing.T) {
+	f, host, port := startFakeRPC(t)
+	tests := []struct {
+		name     string
+		reply    func(w http.ResponseWriter)
+		wantHash string
+		wantCode int
+		wantErr  string
+	}{
+		{"accepted", rpcResult(0, "ABCD", ""), "ABCD", 0, ""},
+		{"check tx rejected", rpcResult(5, "EF01", "bad anchor"), "EF01", 5, ""},
+		{"rpc error", func(w http.ResponseWriter) {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":-1,"error":{"code":-32603,"message":"Internal error","data":"mempool is full"}}`))
+		}, "", 0, "rpc error -32603"},
+		{"http 500", func(w http.ResponseWriter) {
+			http.Error(w, "down", http.StatusInternalServerError)
+		}, "", 0, "status 500"},
+		{"bad json", func(w http.ResponseWriter) {
+			_, _ = w.Write([]byte(`not json`))
+		}, "", 0, "rpc unmarshal"},
+	}
+	tx := anchorTxBytes(7, "root", 3)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f.setReply(tc.reply)
+			hash, code, _, err := BroadcastTxSyncViaRPC(host, port, tx)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hash != tc.wantHash || code != tc.wantCode {
+				t.Fatalf("got hash=%s code=%d, want hash=%s code=%d", hash, code, tc.wantHash, tc.wantCode)
+			}
+		})
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, got := range f.txs {
+		if string(got) != string(tx) {
+			t.Fatalf("request %d tx = %s, want %s", i, got, tx)
+		}
+	}
+
+	if _, _, _, err := BroadcastTxSyncViaRPC("127.0.0.1", 1, tx); err == nil || !strings.Contains(err.Error(), "http get") {
+		t.Fatalf("unreachable node: err = %v", err)
+	}
+}
+
+// ORACLE_TEST_DSN: 마이그레이션을 적용하고 앵커/턴/보상 테이블을 비우는 일회용 DB (없으면 건너뜀)
+func openTestDB(t testing.TB) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("ORACLE_TEST_DSN")
+	if dsn == "" {
+		t.Skip("ORACLE_TEST_DSN not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := dbx.MigrateUp(ctx, db, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx,
+		`TRUNCATE anchor_leaf, anchor_epoch, turn_audit, turn_result, reward_credit CASCADE`); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// 제출 실패한 epoch는 다음 실행에서 같은 root로 재제출되고, 증명은 현재 행 기준으로 검증된다
+func TestRunAnchorOnceRetriesFailedEpoch(t *testing.T) {
+	db := openTestDB(t)
+	f, host, port := startFakeRPC(t)
+	oldHost, oldPort := config.AnchorRPCHost, config.AnchorRPCPort
+	config.AnchorRPCHost, config.AnchorRPCPort = host, port
+	t.Cleanup(func() { config.AnchorRPCHost, config.AnchorRPCPort = oldHost, oldPort })
+
+	ctx := context.Background()
+	for _, id := range []int64{101, 102, 103} {
+		_, claimed, err := dbx.FinalizeTurnWithAuditTx(ctx, db, dbx.TurnRecord{
+			TurnID: id, FullnodeID: "fn1", Creator: "addr" + strconv.FormatInt(id, 10), Weight: 0.5,
+			Params: []byte(`{"beta":0.5}`),
+		}, nil)
+		if err != nil || !claimed {
+			t.Fatalf("finalize turn %d: claimed=%v err=%v", id, claimed, err)
+		}
+	}
+
+	// 1) 체인이 tx를 거부 → epoch는 failed로 남는다
+	f.setReply(rpcResult(5, "REJECTED", "bad anchor"))
+	if err := runAnchorOnce(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := dbx.ListUnsubmittedAnchorEpochs(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Status != "failed" || pending[0].RPCCode != 5 || pending[0].LeafCount != 3 {
+		t.Fatalf("after rejected submit: %+v", pending)
+	}
+	failed := pending[0]
+
+	// 2) 다음 실행: 새 잎이 없어도 failed epoch를 재제출
+	f.setReply(rpcResult(0, "TXHASH", ""))
+	if err := runAnchorOnce(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err = dbx.ListUnsubmittedAnchorEpochs(ctx, db); err != nil || len(pending) != 0 {
+		t.Fatalf("after retry: pending=%+v err=%v", pending, err)
+	}
+	e, err := dbx.GetAnchorEpoch(ctx, db, failed.EpochID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Status != "submitted" || e.TxHash != "TXHASH" || e.SubmittedAt == nil || e.MerkleRoot != failed.MerkleRoot {
+		t.Fatalf("retried epoch: %+v", e)
+	}
+	f.mu.Lock()
+	if len(f.txs) != 2 || string(f.txs[0]) != string(f.txs[1]) {
+		t.Fatalf("expected the same anchor tx twice, got %d requests", len(f.txs))
+	}
+	var body struct {
+		EpochID    int64  `json:"epoch_id"`
+		MerkleRoot string `json:"merkle_root"`
+	}
+	err = json.Unmarshal(f.txs[1], &body)
+	f.mu.Unlock()
+	if err != nil || body.EpochID != failed.EpochID || body.MerkleRoot != failed.MerkleRoot {
+		t.Fatalf("anchor tx body = %+v err=%v", body, err)
+	}
+
+	// 3) 증명: 행이 그대로면 검증, 앵커링 후 행이 바뀌면 실패
+	p, found, err := GetAnchorProof(ctx, db, "turn", "102")
+	if err != nil || !found {
+		t.Fatalf("proof: found=%v err=%v", found, err)
+	}
+	if !p.Verified || !p.RowIntact || p.LiveLeafHash != p.LeafHash {
+		t.Fatalf("untouched row not verified: %+v", p)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE turn_result SET creator = 'mallory' WHERE turn_id = '102'`); err != nil {
+		t.Fatal(err)
+	}
+	if p, _, err = GetAnchorProof(ctx, db, "turn", "102"); err != nil || p.Verified || p.RowIntact {
+		t.Fatalf("tampered row verified: %+v err=%v", p, err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE turn_result SET content_hash = 'x' WHERE turn_id = '103'`); err != nil {
+		t.Fatal(err)
+	}
+	if p, _, err = GetAnchorProof(ctx, db, "turn", "103"); err != nil || p.Verified || p.LiveLeafHash == p.LeafHash {
+		t.Fatalf("rewritten content_hash verified: %+v err=%v", p, err)
+	}
+}
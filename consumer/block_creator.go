@@ -3,16 +3,9 @@ package consumer
 
 import (
 	"context"
-	"crypto/sha256"
 	"database/sql"
-	"encoding/binary"
 	"encoding/json"
 	"fmt"
-	"math"
-	"math/rand"
-	"os"
-	"sort"
-	"strconv"
 	"time"
 
 	"oracle/config"
@@ -20,7 +13,6 @@ import (
 	"oracle/metrics"
 
 	"github.com/IBM/sarama"
-	"github.com/lib/pq"
 )
 
 type selNodeRow struct {
@@ -34,26 +26,11 @@ type selNodeRow struct {
 	f    float64 // (룰렛 누적확률 CDF)
 }
 
-func printSelectionHeader(turn int, beta, sumX, sumSig float64) {
-	fmt.Println("[Select] --------------------------------------------------------------")
-	fmt.Printf("[Select] TURN=%d  beta=%.6f  SumX=%.6f  SumSig=%.6f\n", turn, beta, sumX, sumSig)
-	fmt.Printf("[Select] %-44s | %10s %10s %10s %12s %12s %12s\n",
-		"address", "r0", "re", "rsig", "x_i", "w_i", "P_i")
-	fmt.Println("[Select] -------------------------------------------------------------------------------")
-}
-
-func printSelectionRow(r selNodeRow) {
-	fmt.Printf("[Select] %-44s | %10.6f %10.6f %10.6f %12.6f %12.6f %12.8f\n",
-		r.addr, r.r0, r.re, r.rsig, r.x, r.w, r.p)
-}
-
 func printWinner(turn int, seed int64, randU float64, winner selNodeRow, n int) {
 	fmt.Printf("[Select] WINNER=%s  P=%.8f (w=%.6f)  rand=%.8f  cand=%d  seed=%d\n",
 		winner.addr, winner.p, winner.w, randU, n, seed)
 }
 
-func debugRouletteOn() bool { return os.Getenv("DEBUG_ROULETTE") == "1" }
-
 // ---- 메시지 스키마 ----
 type Contributor struct {
 	Address   string `json:"address,omitempty"`
@@ -62,35 +39,41 @@ type Contributor struct {
 type BlockContributorMsg struct {
 	FullnodeID   string        `json:"fullnode_id"`
 	Contributors []Contributor `json:"contributors"`
+	Height       int64         `json:"height"` // 논리 턴 식별자(필수): quorum 묶음 + 재요청 멱등 키
 }
 type BlockCreatorMsg struct {
 	Creator      string  `json:"creator"`
 	Contribution float64 `json:"contribution"` // 디버그용: 최종 가중치(=w_i)
 	FullnodeID   string  `json:"fullnode_id"`
+	TurnID       int64   `json:"turn_id"`
+	Replayed     bool    `json:"replayed,omitempty"` // 이미 결정된 턴의 저장 결과 재송신
 }
 
 // StartBlockCreatorConsumer
 // - TopicContributors를 구독
-// - w_i = β·x_i + (1-β)·r_i 기반 룰렛휠로 1명 선발
-// - 결과를 TopicBlockCreator로 송신
+// - ContributorQuorum > 1 이면 같은 턴의 풀노드 보고를 모아 조정 후 선발
+// - 선발/송신은 selectAndPublish
 func StartBlockCreatorConsumer(db *sql.DB, producer sarama.SyncProducer) error {
-	// [SCHEMA BOOTSTRAP] main.go를 건드리지 않고 여기서 1회 보장
-
 	go func() {
 		if err := metrics.InitAndServe(":9090"); err != nil {
 			fmt.Println("[Metrics] server error:", err)
 		}
 	}()
-	ctxIdx, cancelIdx := context.WithTimeout(context.Background(), 5*time.Second)
-	// turn_id 정수 시퀀스를 쓰는지 여부에 맞게 true/false
-	_ = dbx.EnsureFairnessIndexes(ctxIdx, db, true)
-	cancelIdx()
-	ctxInit, cancelInit := context.WithTimeout(context.Background(), 5*time.Second)
-	if err := dbx.BootstrapTurnTables(ctxInit, db); err != nil {
-		cancelInit()
-		return fmt.Errorf("schema bootstrap failed: %w", err)
+	StartTurnChainPublisher(db, producer)
+	{
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		n, maxID, err := dbx.LegacyTurnRange(ctx, db)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("legacy turn lookup: %w", err)
+		}
+		if n > 0 {
+			fmt.Printf("[BlockCreator] %d legacy offset-keyed turns (max turn_id=%d): heights that collide with them are refused\n", n, maxID)
+		}
+	}
+	if config.CandidateIndexOn {
+		StartCandidateIndex(context.Background(), db)
 	}
-	cancelInit()
 
 	cfg := sarama.NewConfig()
 	cfg.Version = sarama.V2_1_0_0
@@ -107,6 +90,17 @@ func StartBlockCreatorConsumer(db *sql.DB, producer sarama.SyncProducer) error {
 		return err
 	}
 
+	// 다중 풀노드 quorum: 보고를 모아 조정한 뒤 한 번만 선발
+	var collector *contributorQuorum
+	if config.ContributorQuorum > 1 {
+		collector = newContributorQuorum(config.ContributorQuorum, config.QuorumMinOnTimeout, config.QuorumTimeout)
+		go func() {
+			for turn := range collector.Ready() {
+				selectAndPublish(db, producer, turn)
+			}
+		}()
+	}
+
 	go func() {
 		defer func() { _ = pc.Close(); _ = cons.Close() }()
 
@@ -121,596 +115,168 @@ func StartBlockCreatorConsumer(db *sql.DB, producer sarama.SyncProducer) error {
 				continue
 			}
 
-			voteAddrs, err := FetchVoteAddresses(db) // vote_counter에서 count>0 주소
-			if err != nil {
-				fmt.Println("FetchVoteAddresses err:", err)
-				// 최소 기능 유지: voteAddrs가 nil이면 빈 맵으로 대체
-				voteAddrs = map[string]struct{}{}
-			}
-			eligibleContributors := UnionContributorsWithVoteOnly(data.Contributors, voteAddrs)
-
-			// ★ 합집합 기준으로 검증(빈 기여자 선탈락 금지)
-			if len(eligibleContributors) == 0 {
-				fmt.Println("[BlockCreator] no candidates (contributors ∪ vote-only empty)")
-				continue
-			}
-
-			// 1) 주소 목록 (합집합 기준)
-			addrs := make([]string, 0, len(eligibleContributors))
-			for _, c := range eligibleContributors {
-				if c.Address != "" {
-					addrs = append(addrs, c.Address)
-				}
-			}
-			if len(addrs) == 0 {
-				fmt.Println("[BlockCreator] no valid addresses")
+			// 턴 키는 풀노드 height뿐 (offset으로 대신하면 저장된 다른 턴과 키가 겹친다)
+			if data.Height <= 0 {
+				fmt.Printf("[BlockCreator] report without height from fullnode=%s ignored (offset=%d)\n", data.FullnodeID, m.Offset)
 				continue
 			}
 
-			// 2) vote_counter에서 누적 점수 조회
-			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-			scoreMap, err := dbx.GetVoteCountsByAddresses(ctx, db, addrs)
-			cancel()
-			if err != nil {
-				fmt.Printf("[BlockCreator] vote_counter query failed: %v\n", err)
-				scoreMap = map[string]float64{}
-			}
-
-			// 3) x_i = e_i/E, r_i = count_i / sum(count)
-			var E float64
-			energy := make(map[string]float64, len(addrs)) // 단위 상관없음(합으로만 사용)
-			for _, c := range eligibleContributors {
-				if c.Address == "" {
+			// quorum 모드에서는 미등록 fullnode_id로 보고 수를 부풀릴 수 없도록 등록 풀노드만 집계
+			if collector != nil && config.QuorumRequireRegistered {
+				ctxReg, cancelReg := context.WithTimeout(context.Background(), 2*time.Second)
+				ok, err := dbx.IsActiveFullnode(ctxReg, db, data.FullnodeID)
+				cancelReg()
+				if err != nil || !ok {
+					fmt.Printf("[BlockCreator] report from unregistered fullnode=%s ignored (err=%v)\n", data.FullnodeID, err)
 					continue
 				}
-				ekwh, _ := strconv.ParseFloat(c.EnergyKwh, 64) // 실패 시 0
-				if ekwh < 0 {
-					ekwh = 0
-				}
-				energy[c.Address] = ekwh
-				E += ekwh
-			}
-			var S float64
-			for _, a := range addrs {
-				S += scoreMap[a]
-			}
-			if E == 0 {
-				fmt.Println("[BlockCreator] note: E==0 (no energy this turn)")
-			}
-			if S == 0 {
-				fmt.Println("[BlockCreator] note: S==0 (no votes among union candidates)")
-			}
-			x := make(map[string]float64, len(addrs))
-			rv := make(map[string]float64, len(addrs))
-			for _, a := range addrs {
-				if E > 0 {
-					x[a] = energy[a] / E
-				} else {
-					x[a] = 0
-				}
-				if S > 0 {
-					rv[a] = scoreMap[a] / S
-				} else {
-					rv[a] = 0
-				}
-			}
-
-			// 4) w_i = β·x_i + (1-β)·r_i + ε  (룰렛휠)
-			// [SAFE] eps/beta를 설정에서 읽고, beta 클램프
-			eps := config.RouletteEps
-			if eps <= 0 {
-				eps = 1e-12
-			}
-			beta := config.BlockSelectBeta
-			if beta < 0 {
-				beta = 0
-			}
-			if beta > 1 {
-				beta = 1
-			}
-
-			type pair struct {
-				addr string
-				w    float64
-				p    float64
-				f    float64
-			}
-			ps := make([]pair, 0, len(addrs))
-			var W float64
-			for _, a := range addrs {
-				w := beta*x[a] + (1.0-beta)*rv[a]
-				if w < 0 {
-					w = 0
-				}
-				// (선택) NaN/Inf 방어
-				if math.IsNaN(w) || math.IsInf(w, 0) {
-					w = 0
-				}
-				w += eps
-				ps = append(ps, pair{addr: a, w: w})
-				W += w
-			}
-			if len(ps) == 0 {
-				fmt.Println("[BlockCreator] no candidates after weighting")
-				continue
-			}
-
-			// [SAFE] 만약 모든 w가 0+eps 수준이라 W가 eps*len(addrs)에 매우 가깝더라도 정상 동작.
-			// 극히 드문 케이스로 W<=0이면 균등 분포로 대체.
-			if W <= 0 {
-				fmt.Println("[BlockCreator] note: W<=0 -> fallback to uniform weights")
-				ps = ps[:0]
-				for _, a := range addrs {
-					w := 1.0 // 균등
-					ps = append(ps, pair{addr: a, w: w})
-				}
-				W = float64(len(addrs))
-			}
-
-			var stats map[string]struct {
-				WinsInWindow int
-				ExceedTurnID sql.NullInt64
-			}
-
-			if config.FairFeatureOn {
-				// ---- 2-1) 현재 턴 ID 결정 ----
-				// Kafka 파티션 오프셋을 "턴 번호"로 쓰면 일관된 BIGINT 시퀀스를 얻을 수 있음.
-				// (주의) turn_result.turn_id 가 BIGINT 타입이라고 가정.
-				// 만약 turn_id가 TEXT라면, fetchWinStatsForWindow를 created_at 기반 쿼리로 바꿔야 함.
-				currentTurn := int64(m.Offset) // 필요시 +1 해도 무방
-				ctxFair, cancelFair := context.WithTimeout(context.Background(), 2*time.Second)
-				stats, err = fetchWinStatsForWindow(ctxFair, db, currentTurn, config.FairWinWindowN, config.FairWinCapM, addrs)
-				cancelFair()
-				if err != nil {
-					fmt.Println("[Fairness] fetchWinStats error:", err)
-				} else {
-					// addr -> ps index 매핑 (ps에서 w를 바로 업데이트하기 위함)
-					idx := make(map[string]int, len(ps))
-					for i := range ps {
-						idx[ps[i].addr] = i
-					}
-
-					// ---- 2-3) 패널티 적용 (ramp/fixed) ----
-					for a, s := range stats {
-						// 윈도우 내 승리 횟수가 M 초과 & (M+1)번째 최신 승리 턴 존재
-						if s.WinsInWindow > config.FairWinCapM && s.ExceedTurnID.Valid {
-							exceedTurn := s.ExceedTurnID.Int64
-							// R = 남은 패널티 턴수 = K - (currentTurn - exceedTurn)
-							R := config.FairSoftK - int(currentTurn-exceedTurn)
-							if R > 0 {
-								if i, ok := idx[a]; ok {
-									switch config.FairSoftMode {
-									case "fixed":
-										ps[i].w *= config.FairSoftGamma
-									default: // "ramp"
-										ps[i].w *= math.Pow(config.FairSoftGamma, float64(R))
-									}
-								}
-							}
-						}
-					}
-
-					// ---- 2-4) 패널티 적용 후 W 재계산 ----
-					W = 0
-					for i := range ps {
-						// 수치 안정성 보호
-						if ps[i].w < 0 || math.IsNaN(ps[i].w) || math.IsInf(ps[i].w, 0) {
-							ps[i].w = 0
-						}
-						W += ps[i].w
-					}
-					if W <= 0 {
-						// 만약 모든 가중치가 0이 되었다면 균등 분포로 복구
-						for i := range ps {
-							ps[i].w = 1.0
-						}
-						W = float64(len(ps))
-						fmt.Println("[Fairness] note: all-zero after penalty -> uniform fallback")
-					}
-				}
-			}
-			// ============================================================
-			// 5) P_i, F_i 계산 (주소 정렬로 재현성: map 반복 순서 제거)
-			sort.Slice(ps, func(i, j int) bool { return ps[i].addr < ps[j].addr })
-			acc := 0.0
-			for i := range ps {
-				if W > 0 {
-					ps[i].p = ps[i].w / W
-				} else {
-					ps[i].p = 0
-				}
-				acc += ps[i].p
-				ps[i].f = acc
 			}
-			ps[len(ps)-1].f = 1.0 // 수치오차 보호
-
-			// if debugRouletteOn() {
-			// 	fmt.Println("[Roulette] ===== Candidate Table =====")
-			// 	fmt.Printf("[Roulette] Beta=%.3f  E=%.6f  S=%.6f  (eps=1e-12)\n", beta, E, S)
-			// 	fmt.Printf("[Roulette] %-44s | %10s %10s %10s %12s %12s %12s\n",
-			// 		"address", "e_i", "x_i", "r_i", "w_i", "P_i", "F_i")
-			// 	fmt.Println("[Roulette] -----------------------------------------------------------------------------------------------")
-			// 	for _, row := range ps {
-			// 		a := row.addr
-			// 		ei := energy[a] // 입력 에너지(단위 무관)
-			// 		xi := x[a]      // 발전 참여도
-			// 		ri := rv[a]     // 서명 참여도
-			// 		wi := row.w     // 최종 가중치
-			// 		Pi := row.p     // 최종 확률
-			// 		Fi := row.f     // 누적 경계
-			// 		fmt.Printf("[Roulette] %-44s | %10.4f %10.6f %10.6f %12.8f %12.8f %12.8f\n",
-			// 			a, ei, xi, ri, wi, Pi, Fi)
-			// 	}
-			// 	fmt.Println("[Roulette] ============================================================")
-			// }
-			pcapTriggered := false
 
-			if config.EnablePCap {
-				// 1) 캡 적용
-				if pcapTriggered {
-					metrics.FairPcapAppliedGauge.Set(1)
-				} else {
-					metrics.FairPcapAppliedGauge.Set(0)
-				}
-				capped := make([]bool, len(ps))
-				sumCapped := 0.0
-				sumUncapped := 0.0
-				for i := range ps {
-					if ps[i].p > config.Pcap {
-						sumCapped += config.Pcap
-						capped[i] = true
-					} else {
-						sumUncapped += ps[i].p
-					}
-				}
-				if sumCapped > 0 && sumUncapped > 0 {
-					// 2) 잔여 확률 재분배
-					leftover := 1.0 - sumCapped
-					for i := range ps {
-						if capped[i] {
-							ps[i].p = config.Pcap
-						} else {
-							// 비례 재분배
-							ps[i].p = (ps[i].p / sumUncapped) * leftover
-						}
-					}
-					// 3) F_i 재계산
-					acc = 0.0
-					for i := range ps {
-						acc += ps[i].p
-						ps[i].f = acc
-					}
-					ps[len(ps)-1].f = 1.0
-				}
-				// 극단 케이스: 모두 Pcap 이하라면 아무 변화 없음
-			}
-
-			{
-				// 주의: 현재 코드엔 r0/re 분해가 따로 없으므로
-				//      "r0=0, re=rv[a], rsig=re"로 먼저 찍고,
-				//      추후 너의 로직에서 r0/re를 분리하면 그대로 값만 바꿔주면 됨.
-				var sumX, sumSig float64
-				for _, a := range addrs {
-					sumX += x[a]
-					sumSig += rv[a]
-				}
-
-				// 턴 번호는 카프카 오프셋을 이미 "turn" 개념으로 쓰고 있으니 재활용
-				currentTurn := int64(m.Offset)
-				printSelectionHeader(int(currentTurn), beta, sumX, sumSig)
-
-				for _, row := range ps {
-					a := row.addr
-					r0 := 0.0     // (1) 서명 기본 (지금은 분해값 없음 → 0)
-					re := rv[a]   // (2) 서명 추가 (지금은 전체를 re로 간주)
-					rs := r0 + re // (3)
-					xx := x[a]    // (4) 태양광 (정규화 x_i)
-					printSelectionRow(selNodeRow{
-						addr: a, r0: r0, re: re, rsig: rs,
-						x: xx, w: row.w, p: row.p, f: row.f,
-					})
-				}
-
-				// (선택) 합 검증
-				var pSum float64
-				for _, row := range ps {
-					pSum += row.p
-				}
-				fmt.Printf("[Select] p_sum=%.12f\n", pSum)
-			}
-
-			// 6) 재현 가능한 난수 시드: FullnodeID + topic/partition/offset
-			seedMaterial := fmt.Sprintf("%s:%s:%d:%d", data.FullnodeID, m.Topic, m.Partition, m.Offset)
-			sum := sha256.Sum256([]byte(seedMaterial))
-			seed := int64(binary.LittleEndian.Uint64(sum[:8]))
-			u := rand.New(rand.NewSource(seed)).Float64()
-
-			// 7) 최초 F_i >= u 인 구간의 주소가 당첨
-			winner := ps[len(ps)-1].addr
-			winnerW := ps[len(ps)-1].w
-			for _, v := range ps {
-				if u <= v.f {
-					winner = v.addr
-					winnerW = v.w
-					break
-				}
-			}
-			penalized := 0
-			maxPenalty := 1.0
-			{
-				// winner의 p,f도 찾아서 담아 출력
-				winRow := selNodeRow{addr: winner, w: winnerW}
-				for _, v := range ps {
-					if v.addr == winner {
-						winRow.p = v.p
-						winRow.f = v.f
-						break
-					}
-				}
-				// r0/re/rsig/x는 맵에서 복원
-				r0 := 0.0 // (현재 분해 없음)
-				re := rv[winner]
-				rs := r0 + re
-				xx := x[winner]
-				winRow.r0, winRow.re, winRow.rsig, winRow.x = r0, re, rs, xx
-
-				// 현재 턴(오프셋), 시드/난수(u)는 기존 변수 재사용
-				currentTurn := int64(m.Offset)
-				printWinner(int(currentTurn), seed, u, winRow, len(ps))
-			}
-			for _, a := range addrs {
-				s, ok := stats[a] // fetchWinStatsForWindow 결과(이미 위에서 구함)
-				if ok && s.WinsInWindow > config.FairWinCapM && s.ExceedTurnID.Valid {
-					R := config.FairSoftK - int(int64(m.Offset)-s.ExceedTurnID.Int64)
-					if R > 0 {
-						penalized++
-						// ramp 기준의 이론상 penalty 값 (로그용)
-						p := config.FairSoftGamma
-						if config.FairSoftMode != "fixed" {
-							p = math.Pow(config.FairSoftGamma, float64(R))
-						}
-						if p < maxPenalty {
-							maxPenalty = p
-						}
-					}
-				}
-			}
-			fmt.Printf("[FairnessSummary] turn=%d fullnode=%s winner=%s penalized=%d maxPenalty=%.3f candidates=%d\n",
-				m.Offset, data.FullnodeID, winner, penalized, maxPenalty, len(addrs))
-			if config.FairFeatureOn && stats != nil {
-				penalized := 0
-				maxPenalty := 1.0
-				currentTurn := int64(m.Offset)
-				for _, a := range addrs {
-					if s, ok := stats[a]; ok && s.WinsInWindow > config.FairWinCapM && s.ExceedTurnID.Valid {
-						R := config.FairSoftK - int(currentTurn-s.ExceedTurnID.Int64)
-						if R > 0 {
-							penalized++
-							p := config.FairSoftGamma
-							if config.FairSoftMode != "fixed" {
-								p = math.Pow(config.FairSoftGamma, float64(R))
-							}
-							if p < maxPenalty {
-								maxPenalty = p
-							}
-						}
-					}
-				}
-				metrics.FairPenalizedGauge.Set(float64(penalized))
-				metrics.FairMaxPenaltyGauge.Set(maxPenalty)
-				metrics.FairCandidatesGauge.Set(float64(len(addrs)))
-			}
-			msg := BlockCreatorMsg{
-				Creator:      winner,
-				Contribution: winnerW,
+			rep := fullnodeReport{
 				FullnodeID:   data.FullnodeID,
+				Contributors: data.Contributors,
+				Height:       data.Height,
 			}
-			payload, err := json.Marshal(msg)
-			if err != nil {
-				fmt.Printf("[BlockCreator] marshal failed: %v\n", err)
+			// 단일 풀노드 모드: 기존처럼 메시지마다 즉시 선발
+			if collector == nil {
+				selectAndPublish(db, producer, rep.singleTurn())
 				continue
 			}
-			out := &sarama.ProducerMessage{
-				Topic: config.TopicBlockCreator,
-				Value: sarama.ByteEncoder(payload),
-			}
-			_, _, err = producer.SendMessage(out)
-			if err != nil {
-				fmt.Printf("[BlockCreator] send failed: %v\n", err)
-			} else {
-				fmt.Printf("[BlockCreator] sent → creator=%s w=%.6f seed=%s fullnode=%s\n",
-					msg.Creator, msg.Contribution, seedMaterial, msg.FullnodeID)
-
-				// === [3단계 연결] 송신 성공 시 턴 종료 처리 ===
-				turnID := int64(m.Offset) // Kafka offset을 turn_id로 사용(정수)
-				ctx2, cancel2 := context.WithTimeout(context.Background(), 5*time.Second)
-				err2 := dbx.FinalizeTurnNoResetTx(ctx2, db, turnID, data.FullnodeID, winner, winnerW)
-				cancel2()
-				if err2 != nil {
-					fmt.Printf("[BlockCreator] finalize-turn failed: %v (turn_id=%d)\n", err2, turnID)
-				} else {
-					fmt.Printf("[BlockCreator] finalize-turn OK (turn_id=%d)\n", turnID)
-				}
-			}
+			collector.Add(rep)
 		}
 	}()
 
 	return nil
 }
 
-func DryRunRoulette(contributors []Contributor, voteMap map[string]float64, beta float64, seedMaterial string) (string, float64, float64) {
-	// 1) 주소 및 에너지 합
-	addrs := make([]string, 0, len(contributors))
-	energy := make(map[string]float64, len(contributors))
-	var E float64
-	for _, c := range contributors {
-		if c.Address == "" {
-			continue
-		}
-		addrs = append(addrs, c.Address)
-		ekwh, _ := strconv.ParseFloat(c.EnergyKwh, 64)
-		if ekwh < 0 {
-			ekwh = 0
-		}
-		energy[c.Address] = ekwh // 단위 무관
-		E += ekwh
-	}
-	if len(addrs) == 0 {
-		fmt.Println("[DryRun] no valid contributors")
-		return "", 0, 0
-	}
+// contributorTurn: 선발 1회분 입력 (단일 보고 또는 quorum 조정 결과)
+type contributorTurn struct {
+	FullnodeID    string
+	Contributors  []Contributor
+	TurnID        int64  // 공정성 창/턴 결과 키 (풀노드 height)
+	SeedMaterial  string // 재현 가능한 난수 시드 재료
+	Reporters     []string
+	Disagreements []dbx.ContributorDisagreement
+	ReplayOnly    bool // 저장 결과가 없으면 선발하지 않음 (quorum 종료 후 늦은 보고)
+}
 
-	// 2) 점수 합
-	var S float64
-	for _, a := range addrs {
-		S += voteMap[a]
+// selectAndPublish
+// - w_i = β·x_i + (1-β)·r_i 기반 룰렛휠로 1명 선발
+// - 턴 결과/감사 기록을 저장(turn_id 선점)한 뒤 저장된 결과를 TopicBlockCreator로 송신
+func selectAndPublish(db *sql.DB, producer sarama.SyncProducer, turn contributorTurn) {
+	// 0) 이미 결정된 턴이면 재추첨하지 않고 저장 결과 재송신
+	inputHash := contributorsInputHash(turn.Contributors)
+	if replayDecidedTurn(db, producer, turn, inputHash) {
+		return
 	}
-
-	if E == 0 {
-		fmt.Println("[BlockCreator] note: E==0 (no energy this turn)")
+	if turn.ReplayOnly {
+		fmt.Printf("[Replay] turn=%d has no stored result; late report not selected\n", turn.TurnID)
+		return
 	}
-	if S == 0 {
-		fmt.Println("[BlockCreator] note: S==0 (no votes among union candidates)")
+
+	// vote-only 후보(count>0)와 점수를 한 스냅샷에서 읽는다
+	snap := readSelectionVotes(db, turn.Contributors)
+	sel, err := computeSelection(db, turn, snap, governance.TurnParams(currentTurnParams(), turn.TurnID))
+	if err != nil {
+		fmt.Println("[BlockCreator]", err)
+		return
 	}
-	// 3) x_i, r_i
-	x := make(map[string]float64, len(addrs))
-	rv := make(map[string]float64, len(addrs))
-	for _, a := range addrs {
-		if E > 0 {
-			x[a] = energy[a] / E
-		} else {
-			x[a] = 0
-		}
-		if S > 0 {
-			rv[a] = voteMap[a] / S
+	ps, addrs, x, rv := sel.Table, sel.Addrs, sel.X, sel.R
+	winner, winnerW := sel.Winner, sel.WinnerW
+	seedMaterial := turn.SeedMaterial
+	if config.EnablePCap {
+		if sel.Params.PcapTriggered {
+			metrics.FairPcapAppliedGauge.Set(1)
 		} else {
-			rv[a] = 0
+			metrics.FairPcapAppliedGauge.Set(0)
 		}
 	}
 
-	// 4) w_i = β·x_i + (1-β)·r_i + eps
-	const eps = 1e-12
-	if beta < 0 {
-		beta = 0
-	}
-	if beta > 1 {
-		beta = 1
-	}
-	type pair struct {
-		addr string
-		w    float64
-		p    float64
-		f    float64
-	}
-	ps := make([]pair, 0, len(addrs))
-	var W float64
-	for _, a := range addrs {
-		w := beta*x[a] + (1.0-beta)*rv[a]
-		if w < 0 {
-			w = 0
+	{
+		// winner의 p,f도 찾아서 담아 출력
+		winRow := selNodeRow{addr: winner, w: winnerW}
+		for _, v := range ps {
+			if v.addr == winner {
+				winRow.p = v.p
+				winRow.f = v.f
+				break
+			}
 		}
-		w += eps
-		ps = append(ps, pair{addr: a, w: w})
-		W += w
+		winRow.r0, winRow.re, winRow.rsig, winRow.x = 0, rv[winner], rv[winner], x[winner]
+		printWinner(int(turn.TurnID), sel.Params.Seed, sel.Params.RandU, winRow, len(ps))
 	}
-
-	// 정렬(재현성)
-	sort.Slice(ps, func(i, j int) bool { return ps[i].addr < ps[j].addr })
-
-	// P_i, F_i
-	acc := 0.0
-	for i := range ps {
-		if W > 0 {
-			ps[i].p = ps[i].w / W
-		} else {
-			ps[i].p = 0
+	penalized := 0
+	maxPenalty := 1.0
+	for _, row := range ps {
+		if row.penaltyR > 0 {
+			penalized++
+			if row.penalty < maxPenalty {
+				maxPenalty = row.penalty
+			}
 		}
-		acc += ps[i].p
-		ps[i].f = acc
 	}
-	if len(ps) > 0 {
-		ps[len(ps)-1].f = 1.0
+	fmt.Printf("[FairnessSummary] turn=%d fullnode=%s winner=%s penalized=%d maxPenalty=%.3f candidates=%d\n",
+		turn.TurnID, turn.FullnodeID, winner, penalized, maxPenalty, len(addrs))
+	if config.FairFeatureOn && sel.Stats != nil {
+		metrics.FairPenalizedGauge.Set(float64(penalized))
+		metrics.FairMaxPenaltyGauge.Set(maxPenalty)
+		metrics.FairCandidatesGauge.Set(float64(len(addrs)))
 	}
-
-	// 디버그 표 출력
-	fmt.Println("[DryRun] ===== Candidate Table =====")
-	fmt.Printf("[DryRun] Beta=%.3f  E=%.6f  S=%.6f  (eps=1e-12)\n", beta, E, S)
-	fmt.Printf("[DryRun] %-44s | %10s %10s %10s %12s %12s %12s\n",
-		"address", "e_i", "x_i", "r_i", "w_i", "P_i", "F_i")
-	fmt.Println("[DryRun] -----------------------------------------------------------------------------------------------")
-	for _, row := range ps {
-		a := row.addr
-		ei := energy[a]
-		xi := x[a]
-		ri := rv[a]
-		fmt.Printf("[DryRun] %-44s | %10.4f %10.6f %10.6f %12.8f %12.8f %12.8f\n",
-			a, ei, xi, ri, row.w, row.p, row.f)
+	// 1) 결과를 먼저 저장하며 turn_id를 선점한다. 송신은 커밋 이후에만 (다른 배달이 먼저 결정했으면 그 결과를 재송신)
+	turnID := turn.TurnID
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 5*time.Second)
+	audit := buildTurnAudit(ps, sel.Excluded, sel.Candidates, sel.EntityOf, sel.Scores, x, rv)
+	stored, claimed, err := dbx.FinalizeTurnWithAuditTx(ctx2, db, dbx.TurnRecord{
+		TurnID:     turnID,
+		FullnodeID: turn.FullnodeID,
+		Creator:    winner,
+		Weight:     winnerW,
+		InputHash:  inputHash,
+		SnapshotID: snap.SnapshotID,
+		Params:     sel.Params.marshal(),
+	}, audit)
+	cancel2()
+	if err != nil {
+		// 저장하지 못한 당첨자는 송신하지 않는다 (풀노드 재요청 시 다시 선발)
+		fmt.Printf("[BlockCreator] finalize-turn failed; not sent: %v (turn_id=%d)\n", err, turnID)
+		return
 	}
-	fmt.Println("[DryRun] ============================================================")
-
-	// 난수 시드
-	sum := sha256.Sum256([]byte(seedMaterial))
-	seed := int64(binary.LittleEndian.Uint64(sum[:8]))
-	u := rand.New(rand.NewSource(seed)).Float64()
-
-	// 선택
-	winner := ps[len(ps)-1].addr
-	winnerW := ps[len(ps)-1].w
-	winnerP := ps[len(ps)-1].p
-	for _, v := range ps {
-		if u <= v.f {
-			winner = v.addr
-			winnerW = v.w
-			winnerP = v.p
-			break
+	if !claimed {
+		metrics.TurnReplayCounter.Inc()
+		if stored.InputHash != "" && stored.InputHash != inputHash {
+			metrics.TurnConflictCounter.Inc()
 		}
+		fmt.Printf("[BlockCreator] turn=%d decided concurrently → sending stored creator=%s (drawn %s discarded)\n",
+			turnID, stored.Creator, winner)
+	} else {
+		fmt.Printf("[BlockCreator] finalize-turn OK (turn_id=%d)\n", turnID)
 	}
 
-	fmt.Printf("[DryRun] WINNER=%s  w=%.8f  P=%.8f  seed=%s  u=%.8f\n",
-		winner, winnerW, winnerP, seedMaterial, u)
-	return winner, winnerW, winnerP
-}
-
-// currentTurn: 현재 턴 ID (int64), addrs: 후보 주소 배열
-func fetchWinStatsForWindow(ctx context.Context, db *sql.DB, currentTurn int64, N, M int, addrs []string) (map[string]struct {
-	WinsInWindow int
-	ExceedTurnID sql.NullInt64
-}, error) {
-	q := `
-WITH wins AS (
-  SELECT creator, turn_id,
-         ROW_NUMBER() OVER (PARTITION BY creator ORDER BY turn_id DESC) AS rn
-    FROM turn_result
-   WHERE turn_id > $1 - $2
-     AND creator = ANY($3)
-)
-SELECT creator,
-       COUNT(*) FILTER (WHERE rn <= $4) AS wins_in_window,
-       MAX(CASE WHEN rn = $4 THEN turn_id END) AS exceed_turn_id
-  FROM wins
- GROUP BY creator;
-`
-	rows, err := db.QueryContext(ctx, q, currentTurn, N, pq.Array(addrs), M+1)
-	if err != nil {
-		return nil, err
+	// 2) 저장된 결과 송신 (실패하면 풀노드 재요청이 replayDecidedTurn으로 같은 결과를 받는다)
+	msg := BlockCreatorMsg{
+		Creator:      stored.Creator,
+		Contribution: stored.Weight,
+		FullnodeID:   stored.FullnodeID,
+		TurnID:       stored.TurnID,
+		Replayed:     !claimed,
+	}
+	if err := publishBlockCreator(producer, msg); err != nil {
+		fmt.Printf("[BlockCreator] send failed: %v (turn_id=%d)\n", err, turnID)
+	} else {
+		fmt.Printf("[BlockCreator] sent → creator=%s w=%.6f seed=%s fullnode=%s\n",
+			msg.Creator, msg.Contribution, seedMaterial, msg.FullnodeID)
+	}
+	if !claimed {
+		return
 	}
-	defer rows.Close()
 
-	out := make(map[string]struct {
-		WinsInWindow int
-		ExceedTurnID sql.NullInt64
-	})
-	for rows.Next() {
-		var creator string
-		var wins int
-		var ex sql.NullInt64
-		if err := rows.Scan(&creator, &wins, &ex); err != nil {
-			return nil, err
+	observeSelection(db, turnID, ps, winner)
+	if len(turn.Disagreements) > 0 {
+		fmt.Printf("[Quorum] turn=%d reporters=%v disagreements=%d\n", turnID, turn.Reporters, len(turn.Disagreements))
+		ctx3, cancel3 := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := dbx.InsertContributorDisagreements(ctx3, db, turnID, turn.Disagreements); err != nil {
+			fmt.Printf("[Quorum] disagreement save failed: %v (turn_id=%d)\n", err, turnID)
 		}
-		out[creator] = struct {
-			WinsInWindow int
-			ExceedTurnID sql.NullInt64
-		}{WinsInWindow: wins, ExceedTurnID: ex}
+		cancel3()
 	}
-	return out, nil
 }
@@ -21,16 +21,80 @@ type joinedRow struct {
 
 // R0, q*, q10, q90, 사용된 권역 수를 리턴
 func ComputeR0FromJoined(joinedPath string, enableInverse bool, B, qlo, qhi float64) (r0, qstar, q10, q90 float64, regionsUsed int, err error) {
-	rows, err := readJoined(joinedPath)
+	basis, err := loadR0Basis(joinedPath, qlo, qhi)
 	if err != nil {
 		return 0, 0, 0, 0, 0, err
 	}
+	q10, q90 = basis.q10, basis.q90
+	if len(basis.regions) == 0 {
+		return 0, 0, q10, q90, 0, fmt.Errorf("no requested regions present")
+	}
+
+	// 4) q* = mean_r(m_r)
+	var sum float64
+	for _, p := range basis.regions {
+		sum += p.Mr
+	}
+	qstar = sum / float64(len(basis.regions))
+	regionsUsed = len(basis.regions)
+
+	// 5) R0
+	r0 = basis.r0At(qstar, enableInverse, B, qlo, qhi)
+	return r0, qstar, q10, q90, regionsUsed, nil
+}
+
+// 권역별 R0 (q* 대신 그 권역의 m_r 사용). 전국 R0와 같은 분위수/역변환 기준.
+type RegionR0 struct {
+	Region   string  `json:"region"`
+	R0       float64 `json:"r0"`
+	Mr       float64 `json:"m_r"`
+	Stations int     `json:"stations"`
+}
+
+func ComputeRegionalR0FromJoined(joinedPath string, enableInverse bool, B, qlo, qhi float64) ([]RegionR0, error) {
+	basis, err := loadR0Basis(joinedPath, qlo, qhi)
+	if err != nil {
+		return nil, err
+	}
+	if len(basis.regions) == 0 {
+		return nil, fmt.Errorf("no requested regions present")
+	}
+	out := make([]RegionR0, 0, len(basis.regions))
+	for _, p := range basis.regions {
+		out = append(out, RegionR0{
+			Region:   p.Region,
+			R0:       basis.r0At(p.Mr, enableInverse, B, qlo, qhi),
+			Mr:       p.Mr,
+			Stations: p.Stations,
+		})
+	}
+	return out, nil
+}
+
+// 조인 파일에서 뽑은 R0 산출 기준: 전체 유효 x(정렬), q10/q90, 요청 권역별 z 중앙값
+type r0Basis struct {
+	allX     []float64
+	q10, q90 float64
+	regions  []regionMedian // 권역명 순
+}
+
+type regionMedian struct {
+	Region   string
+	Mr       float64
+	Stations int // 서로 다른 관측지점 수
+}
+
+func loadR0Basis(joinedPath string, qlo, qhi float64) (r0Basis, error) {
+	var b r0Basis
+	rows, err := readJoined(joinedPath)
+	if err != nil {
+		return b, err
+	}
 	if len(rows) == 0 {
-		return 0, 0, 0, 0, 0, fmt.Errorf("no joined rows")
+		return b, fmt.Errorf("no joined rows")
 	}
 
 	// 1) 전체 유효 x 수집
-	var allX []float64
 	for _, r := range rows {
 		if r.Irradiance == nil {
 			continue
@@ -39,78 +103,65 @@ func ComputeR0FromJoined(joinedPath string, enableInverse bool, B, qlo, qhi floa
 		if *r.Irradiance <= -9.0 {
 			continue
 		}
-		allX = append(allX, *r.Irradiance)
+		b.allX = append(b.allX, *r.Irradiance)
 	}
-	if len(allX) == 0 {
-		return 0, 0, 0, 0, 0, fmt.Errorf("no valid irradiance values")
+	if len(b.allX) == 0 {
+		return b, fmt.Errorf("no valid irradiance values")
 	}
-	sort.Float64s(allX)
+	sort.Float64s(b.allX)
 
 	// 2) 분위수 계산
-	q10 = quantileSorted(allX, qlo)
-	q90 = quantileSorted(allX, qhi)
-	if q90 == q10 {
-		minv, maxv := allX[0], allX[len(allX)-1]
+	b.q10 = quantileSorted(b.allX, qlo)
+	b.q90 = quantileSorted(b.allX, qhi)
+	if b.q90 == b.q10 {
+		minv, maxv := b.allX[0], b.allX[len(b.allX)-1]
 		if maxv == minv {
-			q10 = minv
-			q90 = minv + 1e-9
+			b.q10 = minv
+			b.q90 = minv + 1e-9
 		} else {
-			q10, q90 = minv, maxv
+			b.q10, b.q90 = minv, maxv
 		}
 	}
 
 	// 3) z-정규화 후 권역별 중앙값 m_r
 	regionZ := map[string][]float64{}
+	regionSt := map[string]map[string]struct{}{}
 	for _, r := range rows {
-		if r.Irradiance == nil || strings.TrimSpace(r.StationID) == "" {
+		sid := strings.TrimSpace(r.StationID)
+		if r.Irradiance == nil || sid == "" {
 			continue
 		}
-		z := toZ(*r.Irradiance, q10, q90) // [0,1]
-		regionZ[strings.TrimSpace(r.Region)] = append(regionZ[strings.TrimSpace(r.Region)], z)
-	}
-
-	type pair struct {
-		Region string
-		Mr     float64
-		N      int
+		region := strings.TrimSpace(r.Region)
+		z := toZ(*r.Irradiance, b.q10, b.q90) // [0,1]
+		regionZ[region] = append(regionZ[region], z)
+		if regionSt[region] == nil {
+			regionSt[region] = map[string]struct{}{}
+		}
+		regionSt[region][sid] = struct{}{}
 	}
-	var plist []pair
 	for region, zs := range regionZ {
-		if len(zs) == 0 {
+		if len(zs) == 0 || !conf.RequestedRegions[region] {
 			continue
 		}
-		m := median(zs)
-		if conf.RequestedRegions[region] {
-			plist = append(plist, pair{Region: region, Mr: m, N: len(zs)})
-		}
+		b.regions = append(b.regions, regionMedian{Region: region, Mr: median(zs), Stations: len(regionSt[region])})
 	}
-	if len(plist) == 0 {
-		return 0, 0, q10, q90, 0, fmt.Errorf("no requested regions present")
-	}
-
-	// 4) q* = mean_r(m_r)
-	var sum float64
-	for _, p := range plist {
-		sum += p.Mr
-	}
-	qstar = sum / float64(len(plist))
-	regionsUsed = len(plist)
+	sort.Slice(b.regions, func(i, j int) bool { return b.regions[i].Region < b.regions[j].Region })
+	return b, nil
+}
 
-	// 5) R0
-	var raw float64
+// q ∈ [0,1] → R0 (enableInverse면 ECDF 역변환으로 MJ/m^2, 아니면 q 그대로) × B
+func (b r0Basis) r0At(q float64, enableInverse bool, B, qlo, qhi float64) float64 {
+	raw := q // [0,1]
 	if enableInverse {
-		p := qlo + qstar*(qhi-qlo)
+		p := qlo + q*(qhi-qlo)
 		if p < 0 {
 			p = 0
 		} else if p > 1 {
 			p = 1
 		}
-		raw = invECDF(allX, p) // MJ/m^2
-	} else {
-		raw = qstar // [0,1]
+		raw = invECDF(b.allX, p) // MJ/m^2
 	}
-	r0 = B * raw
-	return r0, qstar, q10, q90, regionsUsed, nil
+	return B * raw
 }
 
 func readJoined(path string) ([]joinedRow, error) {
@@ -2,7 +2,13 @@
 package consumer
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"time"
+
+	"oracle/config"
+	dbx "oracle/db"
 )
 
 // DB의 vote_counter에서 count > 0 인 주소들을 모두 가져옵니다.
@@ -32,6 +38,7 @@ func FetchVoteAddresses(db *sql.DB) (map[string]struct{}, error) {
 // - 기여자에 이미 있는 주소는 그대로 둡니다.
 // - 기여자에 없는 vote-only 주소는 EnergyKwh="0"인 가상의 Contributor로 추가합니다.
 // - 중복 주소는 자동 제거됩니다.
+// - voteAddrs는 읽기만 합니다 (인덱스 스냅샷 맵을 턴끼리 공유).
 func UnionContributorsWithVoteOnly(contribs []Contributor, voteAddrs map[string]struct{}) []Contributor {
 	seen := make(map[string]struct{}, len(contribs))
 	out := make([]Contributor, 0, len(contribs)+len(voteAddrs))
@@ -46,12 +53,13 @@ func UnionContributorsWithVoteOnly(contribs []Contributor, voteAddrs map[string]
 		}
 		out = append(out, c)
 		seen[c.Address] = struct{}{}
-		// 기여자에 이미 있으면 vote-only 세트에서 제거 (중복 방지)
-		delete(voteAddrs, c.Address)
 	}
 
-	// 2) 남은 vote-only 주소는 EnergyKwh=0으로 추가
+	// 2) 기여자에 없는 vote-only 주소는 EnergyKwh=0으로 추가
 	for addr := range voteAddrs {
+		if _, dup := seen[addr]; dup {
+			continue
+		}
 		out = append(out, Contributor{
 			Address:   addr,
 			EnergyKwh: "0",
@@ -60,3 +68,42 @@ func UnionContributorsWithVoteOnly(contribs []Contributor, voteAddrs map[string]
 
 	return out
 }
+
+// 선발 입력 읽기: 인메모리 인덱스가 준비돼 있으면 사용, 아니면 DB 스냅샷.
+// 스냅샷 트랜잭션 실패 시 기존 개별 조회로 대체 (SnapshotID 빈 값)
+func readSelectionVotes(db *sql.DB, contribs []Contributor) dbx.VoteSnapshot {
+	contribAddrs := make([]string, 0, len(contribs))
+	for _, c := range contribs {
+		if c.Address != "" {
+			contribAddrs = append(contribAddrs, c.Address)
+		}
+	}
+
+	if config.CandidateIndexOn && voteIndex.Ready() {
+		return voteIndex.Snapshot()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	snap, err := dbx.ReadVoteSnapshot(ctx, db, contribAddrs)
+	if err == nil {
+		return snap
+	}
+	fmt.Println("[Snapshot] repeatable-read snapshot failed (fallback: separate reads):", err)
+
+	voteAddrs, err := FetchVoteAddresses(db)
+	if err != nil {
+		fmt.Println("FetchVoteAddresses err:", err)
+		voteAddrs = map[string]struct{}{}
+	}
+	all := append([]string(nil), contribAddrs...)
+	for a := range voteAddrs {
+		all = append(all, a)
+	}
+	scores, err := dbx.GetVoteCountsByAddresses(ctx, db, all)
+	if err != nil {
+		fmt.Printf("[BlockCreator] vote_counter query failed: %v\n", err)
+		scores = map[string]float64{}
+	}
+	return dbx.VoteSnapshot{VoteOnly: voteAddrs, Scores: scores}
+}
@@ -0,0 +1,226 @@
+// oracle/consumer/candidate_index.go
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+
+	"oracle/config"
+	dbx "oracle/db"
+)
+
+// candidateIndex
+// - vote_counter(address -> count)의 인메모리 사본
+// - 턴마다 전체 테이블을 스캔하지 않고 이 사본에서 vote-only 후보/점수를 읽는다
+// - 갱신 경로: 보상 consumer의 upsert 직후 Set, 다른 writer의 변경은 NOTIFY, 유실은 주기적 재동기화
+// - 값은 항상 절대값(count)으로 덮어쓰므로 같은 변경이 여러 경로로 들어와도 중복 누적되지 않는다
+type candidateIndex struct {
+	mu      sync.RWMutex
+	scores  map[string]float64
+	touched map[string]uint64 // 주소별 마지막 Set/Delete 시점의 version (Reload가 그보다 오래된 DB 값으로 덮지 않도록)
+	version uint64            // 변경마다 증가 (턴 기록의 snapshot_id)
+	ready   bool
+	view    *indexView // 마지막으로 만든 읽기 전용 스냅샷 (version이 같으면 턴마다 재사용)
+}
+
+// 한 version의 읽기 전용 사본. Snapshot이 반환한 맵은 여러 턴이 공유하므로 수정하지 않는다
+type indexView struct {
+	version  uint64
+	voteOnly map[string]struct{}
+	scores   map[string]float64
+}
+
+var voteIndex = &candidateIndex{scores: map[string]float64{}, touched: map[string]uint64{}}
+
+func (ix *candidateIndex) Ready() bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.ready
+}
+
+func (ix *candidateIndex) Set(addr string, count float64) {
+	if addr == "" {
+		return
+	}
+	ix.mu.Lock()
+	if old, ok := ix.scores[addr]; !ok || old != count {
+		ix.scores[addr] = count
+		ix.version++
+		ix.touched[addr] = ix.version
+	}
+	ix.mu.Unlock()
+}
+
+func (ix *candidateIndex) Delete(addr string) {
+	ix.mu.Lock()
+	if _, ok := ix.scores[addr]; ok {
+		delete(ix.scores, addr)
+		ix.version++
+		ix.touched[addr] = ix.version
+	}
+	ix.mu.Unlock()
+}
+
+// DB 전체와 재동기화. 사본과 달랐던 주소 수를 반환
+// - 전체 로드는 잠금 밖에서 하므로 그 사이 Set/Delete된 주소는 로드 결과보다 새 값일 수 있다
+// - 로드 시작 시점 version 이후에 바뀐 주소는 사본 값을 유지하고 나머지만 DB 값으로 교체
+func (ix *candidateIndex) Reload(ctx context.Context, db *sql.DB) (int, error) {
+	ix.mu.RLock()
+	start := ix.version
+	ix.mu.RUnlock()
+
+	fresh, err := dbx.LoadAllVoteCounts(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for a, v := range ix.touched {
+		if v <= start {
+			delete(ix.touched, a)
+			continue
+		}
+		if c, ok := ix.scores[a]; ok {
+			fresh[a] = c
+		} else {
+			delete(fresh, a)
+		}
+	}
+	drift := 0
+	for a, c := range fresh {
+		if old, ok := ix.scores[a]; !ok || old != c {
+			drift++
+		}
+	}
+	for a := range ix.scores {
+		if _, ok := fresh[a]; !ok {
+			drift++
+		}
+	}
+	ix.scores = fresh
+	if drift > 0 || !ix.ready {
+		ix.version++
+	}
+	ix.ready = true
+	return drift, nil
+}
+
+// ReadVoteSnapshot과 같은 조회 결과를 사본에서 만든다 (SnapshotID = "index:<version>")
+// - 맵 복사는 version이 바뀐 뒤 첫 턴에만 하고, 변경이 없으면 이전 사본을 그대로 돌려준다
+// - Scores에는 색인된 모든 주소(count <= 0 포함)가 있으므로 기여자 점수도 같은 맵에서 찾는다
+func (ix *candidateIndex) Snapshot() dbx.VoteSnapshot {
+	ix.mu.RLock()
+	v := ix.view
+	if v == nil || v.version != ix.version {
+		v = nil
+	}
+	ix.mu.RUnlock()
+	if v == nil {
+		ix.mu.Lock()
+		if ix.view == nil || ix.view.version != ix.version {
+			ix.view = ix.buildViewLocked()
+		}
+		v = ix.view
+		ix.mu.Unlock()
+	}
+	return dbx.VoteSnapshot{
+		SnapshotID: fmt.Sprintf("index:%d", v.version),
+		VoteOnly:   v.voteOnly,
+		Scores:     v.scores,
+	}
+}
+
+func (ix *candidateIndex) buildViewLocked() *indexView {
+	v := &indexView{
+		version:  ix.version,
+		voteOnly: make(map[string]struct{}, len(ix.scores)),
+		scores:   make(map[string]float64, len(ix.scores)),
+	}
+	for a, c := range ix.scores {
+		v.scores[a] = c
+		if c > 0 {
+			v.voteOnly[a] = struct{}{}
+		}
+	}
+	return v
+}
+
+type voteCounterNotice struct {
+	Address string  `json:"address"`
+	Count   float64 `json:"count"`
+	Deleted bool    `json:"deleted"`
+}
+
+// StartCandidateIndex
+// - 최초 전체 로드 후 LISTEN vote_counter_changed
+// - 재연결 시(그 사이 NOTIFY 유실 가능) 및 CandidateIndexReconcile 주기마다 전체 재동기화
+// - 최초 로드 실패 시 ready=false로 남아 선발은 DB 스냅샷 경로를 사용
+func StartCandidateIndex(ctx context.Context, db *sql.DB) {
+	reload := func(reason string) {
+		c, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		start := time.Now()
+		drift, err := voteIndex.Reload(c, db)
+		if err != nil {
+			fmt.Printf("[CandIndex] reload (%s) failed: %v\n", reason, err)
+			return
+		}
+		if drift > 0 || reason != "periodic" {
+			fmt.Printf("[CandIndex] reload (%s): drift=%d took=%s\n", reason, drift, time.Since(start))
+		}
+	}
+
+	reload("initial")
+
+	listener := pq.NewListener(config.Dsn, 10*time.Second, time.Minute,
+		func(ev pq.ListenerEventType, err error) {
+			switch ev {
+			case pq.ListenerEventReconnected:
+				go reload("reconnect")
+			case pq.ListenerEventConnectionAttemptFailed, pq.ListenerEventDisconnected:
+				fmt.Println("[CandIndex] listener connection problem:", err)
+			}
+		})
+	if err := listener.Listen(dbx.VoteCounterNotifyChannel); err != nil {
+		fmt.Println("[CandIndex] LISTEN failed (periodic reload only):", err)
+	}
+
+	go func() {
+		defer listener.Close()
+		every := config.CandidateIndexReconcile
+		if every <= 0 {
+			every = 5 * time.Minute
+		}
+		t := time.NewTicker(every)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				reload("periodic")
+			case n := <-listener.Notify:
+				if n == nil {
+					// 재연결 직후 pq가 보내는 nil 알림
+					continue
+				}
+				var msg voteCounterNotice
+				if err := json.Unmarshal([]byte(n.Extra), &msg); err != nil {
+					fmt.Println("[CandIndex] bad notify payload:", err)
+					continue
+				}
+				if msg.Deleted {
+					voteIndex.Delete(msg.Address)
+				} else {
+					voteIndex.Set(msg.Address, msg.Count)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,44 @@
+package consumer
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+// 10만 주소 인덱스에서 턴 1회(스냅샷 + 선발) 지연
+// - unchanged: 턴 사이 점수 변경 없음 (스냅샷 재사용)
+// - changed  : 턴마다 한 주소가 바뀜 (스냅샷 재생성)
+func BenchmarkSelectionTurnIndex100k(b *testing.B) {
+	const n = 100_000
+	ix := &candidateIndex{scores: make(map[string]float64, n), touched: map[string]uint64{}, ready: true}
+	for i := 0; i < n; i++ {
+		ix.scores[fmt.Sprintf("addr%06d", i)] = float64(i%500 + 1)
+	}
+	contribs := make([]Contributor, 0, 200)
+	for i := 0; i < 200; i++ {
+		contribs = append(contribs, Contributor{Address: fmt.Sprintf("addr%06d", i*400), EnergyKwh: strconv.Itoa(i + 1)})
+	}
+	p := currentTurnParams()
+	p.EntityGrouping, p.FairOn, p.PcapOn = false, false, false
+	p.GeoMode, p.FloorMode, p.EnergyTransform = GeoOff, "off", EnergyLinear
+	p.Eligibility = eligibilityRules{IncludeVoteOnly: true}
+
+	for _, tc := range []struct {
+		name   string
+		mutate bool
+	}{{"unchanged", false}, {"changed", true}} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if tc.mutate {
+					ix.Set("addr000001", float64(i))
+				}
+				turn := contributorTurn{Contributors: contribs, TurnID: int64(i + 1), SeedMaterial: "bench:height:" + strconv.Itoa(i)}
+				if _, err := computeSelection(nil, turn, ix.Snapshot(), p); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
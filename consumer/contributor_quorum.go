@@ -0,0 +1,237 @@
+// oracle/consumer/contributor_quorum.go
+package consumer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	dbx "oracle/db"
+)
+
+// 풀노드 1곳의 기여자 보고
+type fullnodeReport struct {
+	FullnodeID   string
+	Contributors []Contributor
+	Height       int64 // 풀노드가 보낸 턴/높이 (필수, 0 이하 보고는 수신 단계에서 버림)
+}
+
+// 논리 턴 기준 시드 (재전송·재시작에도 동일)
+func (r fullnodeReport) seedMaterial() string {
+	return fmt.Sprintf("%s:height:%d", r.FullnodeID, r.Height)
+}
+
+// 턴 식별자 = 풀노드 height. turn_result.turn_id / 공정성·권역 창이 모두 이 키 공간을 쓴다
+// (Kafka offset은 height와 값이 겹쳐 다른 턴의 저장 결과를 재송신할 수 있으므로 쓰지 않는다)
+func (r fullnodeReport) turnID() int64 {
+	return r.Height
+}
+
+// quorum 없이 보고 1건으로 바로 선발 (기존 동작)
+func (r fullnodeReport) singleTurn() contributorTurn {
+	return contributorTurn{
+		FullnodeID:   r.FullnodeID,
+		Contributors: r.Contributors,
+		TurnID:       r.turnID(),
+		SeedMaterial: r.seedMaterial(),
+		Reporters:    []string{r.FullnodeID},
+	}
+}
+
+// 같은 턴에 대한 보고 묶음
+type pendingRound struct {
+	key     string
+	first   fullnodeReport
+	reports map[string][]Contributor // fullnode_id -> 기여자 목록 (마지막 보고 우선)
+	timer   *time.Timer
+}
+
+// contributorQuorum
+// - 같은 턴(height)에 대한 풀노드 보고를 모은다
+// - 서로 다른 풀노드 quorum개가 모이거나 timeout이 지나면 목록을 조정해 ready로 내보낸다
+type contributorQuorum struct {
+	mu           sync.Mutex
+	quorum       int
+	minOnTimeout int
+	timeout      time.Duration
+
+	rounds map[string]*pendingRound
+	closed map[string]time.Time // 이미 선발한 height 라운드 (늦은 보고 무시)
+
+	ready chan contributorTurn
+}
+
+func newContributorQuorum(quorum, minOnTimeout int, timeout time.Duration) *contributorQuorum {
+	if minOnTimeout < 1 {
+		minOnTimeout = 1
+	}
+	return &contributorQuorum{
+		quorum:       quorum,
+		minOnTimeout: minOnTimeout,
+		timeout:      timeout,
+		rounds:       map[string]*pendingRound{},
+		closed:       map[string]time.Time{},
+		ready:        make(chan contributorTurn, 64),
+	}
+}
+
+func (q *contributorQuorum) Ready() <-chan contributorTurn { return q.ready }
+
+// Add / expire는 잠금 안에서 내보낼 턴만 정하고, ready 송신은 잠금을 푼 뒤에 한다
+// (ready 버퍼가 차서 송신이 막혀도 다른 보고 수신·timeout 처리가 잠금에서 멈추지 않도록)
+func (q *contributorQuorum) Add(r fullnodeReport) {
+	if t, ok := q.add(r); ok {
+		q.ready <- t
+	}
+}
+
+func (q *contributorQuorum) add(r fullnodeReport) (contributorTurn, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pruneClosed()
+
+	key := "h:" + strconv.FormatInt(r.Height, 10)
+	if _, done := q.closed[key]; done {
+		// 이미 선발한 턴: 재추첨 없이 저장 결과만 재송신하도록 넘김
+		fmt.Printf("[Quorum] late report for decided height=%d (fullnode=%s) → replay only\n", r.Height, r.FullnodeID)
+		t := r.singleTurn()
+		t.ReplayOnly = true
+		return t, true
+	}
+
+	pr, ok := q.rounds[key]
+	if !ok {
+		pr = &pendingRound{key: key, first: r, reports: map[string][]Contributor{}}
+		q.rounds[key] = pr
+		pr.timer = time.AfterFunc(q.timeout, func() { q.expire(key) })
+	}
+	pr.reports[r.FullnodeID] = r.Contributors
+	fmt.Printf("[Quorum] round=%s reports=%d/%d (fullnode=%s)\n", key, len(pr.reports), q.quorum, r.FullnodeID)
+
+	if len(pr.reports) >= q.quorum {
+		pr.timer.Stop()
+		return q.closeLocked(pr), true
+	}
+	return contributorTurn{}, false
+}
+
+func (q *contributorQuorum) expire(key string) {
+	if t, ok := q.expireRound(key); ok {
+		q.ready <- t
+	}
+}
+
+func (q *contributorQuorum) expireRound(key string) (contributorTurn, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pr, ok := q.rounds[key]
+	if !ok {
+		return contributorTurn{}, false
+	}
+	if len(pr.reports) < q.minOnTimeout {
+		fmt.Printf("[Quorum] round=%s timed out with %d report(s) < min %d; dropped\n", key, len(pr.reports), q.minOnTimeout)
+		q.forgetLocked(pr)
+		return contributorTurn{}, false
+	}
+	fmt.Printf("[Quorum] round=%s timed out; selecting with %d/%d report(s)\n", key, len(pr.reports), q.quorum)
+	return q.closeLocked(pr), true
+}
+
+func (q *contributorQuorum) forgetLocked(pr *pendingRound) {
+	delete(q.rounds, pr.key)
+	q.closed[pr.key] = time.Now()
+}
+
+// 라운드를 닫고 조정된 턴을 반환 (송신은 호출자가 잠금 밖에서)
+func (q *contributorQuorum) closeLocked(pr *pendingRound) contributorTurn {
+	q.forgetLocked(pr)
+
+	contribs, disagreements := reconcileReports(pr.reports)
+	reporters := make([]string, 0, len(pr.reports))
+	for id := range pr.reports {
+		reporters = append(reporters, id)
+	}
+	sort.Strings(reporters)
+
+	return contributorTurn{
+		FullnodeID:    pr.first.FullnodeID,
+		Contributors:  contribs,
+		TurnID:        pr.first.turnID(),
+		SeedMaterial:  pr.first.seedMaterial(),
+		Reporters:     reporters,
+		Disagreements: disagreements,
+	}
+}
+
+// 선발이 끝난 height 기록은 10분 뒤 정리
+func (q *contributorQuorum) pruneClosed() {
+	cut := time.Now().Add(-10 * time.Minute)
+	for k, t := range q.closed {
+		if t.Before(cut) {
+			delete(q.closed, k)
+		}
+	}
+}
+
+// reconcileReports
+// - 보고의 과반(> 절반)에 포함된 주소만 남기고, 에너지는 보고값의 중앙값을 사용
+// - 과반 미달 주소, 또는 보고값이 서로 다른 주소는 불일치로 기록
+func reconcileReports(reports map[string][]Contributor) ([]Contributor, []dbx.ContributorDisagreement) {
+	total := len(reports)
+	byAddr := map[string][]float64{}
+	reporters := map[string][]string{}
+	for fid, list := range reports {
+		seen := map[string]struct{}{}
+		for _, c := range list {
+			if c.Address == "" {
+				continue
+			}
+			if _, dup := seen[c.Address]; dup {
+				continue
+			}
+			seen[c.Address] = struct{}{}
+			e, _ := strconv.ParseFloat(c.EnergyKwh, 64)
+			if e < 0 {
+				e = 0
+			}
+			byAddr[c.Address] = append(byAddr[c.Address], e)
+			reporters[c.Address] = append(reporters[c.Address], fid)
+		}
+	}
+
+	addrs := make([]string, 0, len(byAddr))
+	for a := range byAddr {
+		addrs = append(addrs, a)
+	}
+	sort.Strings(addrs)
+
+	out := make([]Contributor, 0, len(addrs))
+	var ds []dbx.ContributorDisagreement
+	for _, a := range addrs {
+		es := byAddr[a]
+		sort.Float64s(es)
+		med := median(es)
+		majority := len(es)*2 > total
+		if majority {
+			out = append(out, Contributor{Address: a, EnergyKwh: strconv.FormatFloat(med, 'f', -1, 64)})
+		}
+		if !majority || es[0] != es[len(es)-1] {
+			by := append([]string(nil), reporters[a]...)
+			sort.Strings(by)
+			ds = append(ds, dbx.ContributorDisagreement{
+				Address:      a,
+				ReportedBy:   by,
+				Reports:      len(es),
+				TotalReports: total,
+				MinKwh:       es[0],
+				MaxKwh:       es[len(es)-1],
+				MedianKwh:    med,
+				Dropped:      !majority,
+			})
+		}
+	}
+	return out, ds
+}
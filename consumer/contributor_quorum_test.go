@@ -0,0 +1,136 @@
+package consumer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReconcileReports(t *testing.T) {
+	c := func(addr, kwh string) Contributor { return Contributor{Address: addr, EnergyKwh: kwh} }
+
+	cases := []struct {
+		name    string
+		reports map[string][]Contributor
+		want    []Contributor
+		dropped []string // 과반 미달로 빠진 주소
+		spread  []string // 채택됐지만 보고값이 엇갈린 주소
+	}{
+		{
+			name: "all agree",
+			reports: map[string][]Contributor{
+				"fn1": {c("a", "1"), c("b", "2")},
+				"fn2": {c("a", "1"), c("b", "2")},
+			},
+			want: []Contributor{c("a", "1"), c("b", "2")},
+		},
+		{
+			name: "strict majority required",
+			reports: map[string][]Contributor{
+				"fn1": {c("a", "1"), c("b", "2")},
+				"fn2": {c("a", "1")},
+			},
+			want:    []Contributor{c("a", "1")},
+			dropped: []string{"b"},
+		},
+		{
+			name: "median of odd reports",
+			reports: map[string][]Contributor{
+				"fn1": {c("a", "1")},
+				"fn2": {c("a", "5")},
+				"fn3": {c("a", "2")},
+			},
+			want:   []Contributor{c("a", "2")},
+			spread: []string{"a"},
+		},
+		{
+			name: "median of even reports",
+			reports: map[string][]Contributor{
+				"fn1": {c("a", "1")},
+				"fn2": {c("a", "3")},
+				"fn3": {c("a", "3"), c("x", "9")},
+				"fn4": {c("a", "7")},
+			},
+			want:    []Contributor{c("a", "3")},
+			dropped: []string{"x"},
+			spread:  []string{"a"},
+		},
+		{
+			name: "duplicate address in one report counted once",
+			reports: map[string][]Contributor{
+				"fn1": {c("a", "1"), c("a", "1")},
+				"fn2": {},
+				"fn3": {c("", "4")},
+			},
+			want:    []Contributor{},
+			dropped: []string{"a"},
+		},
+		{
+			name: "negative energy clamped to zero",
+			reports: map[string][]Contributor{
+				"fn1": {c("a", "-3")},
+			},
+			want: []Contributor{c("a", "0")},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ds := reconcileReports(tc.reports)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("contributors = %v, want %v", got, tc.want)
+			}
+			var dropped, spread []string
+			for _, d := range ds {
+				if d.TotalReports != len(tc.reports) {
+					t.Fatalf("%s: total_reports = %d, want %d", d.Address, d.TotalReports, len(tc.reports))
+				}
+				if d.Dropped {
+					dropped = append(dropped, d.Address)
+				} else {
+					spread = append(spread, d.Address)
+				}
+			}
+			if !reflect.DeepEqual(dropped, tc.dropped) {
+				t.Fatalf("dropped = %v, want %v", dropped, tc.dropped)
+			}
+			if !reflect.DeepEqual(spread, tc.spread) {
+				t.Fatalf("spread = %v, want %v", spread, tc.spread)
+			}
+		})
+	}
+}
+
+// ready 버퍼가 가득 차 송신이 막혀 있어도 다른 보고 처리는 잠금에서 멈추지 않아야 한다
+func TestContributorQuorumSendOutsideLock(t *testing.T) {
+	q := newContributorQuorum(1, 1, time.Minute)
+	h := int64(1)
+	for ; h <= int64(cap(q.ready)); h++ {
+		q.Add(fullnodeReport{FullnodeID: "fn1", Height: h})
+	}
+
+	blocked := make(chan struct{})
+	go func() {
+		q.Add(fullnodeReport{FullnodeID: "fn1", Height: h})
+		close(blocked)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		q.expire("h:999")
+		q.add(fullnodeReport{FullnodeID: "fn1", Height: h + 1})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("quorum lock held while ready send is blocked")
+	}
+
+	<-q.Ready()
+	select {
+	case <-blocked:
+	case <-time.After(2 * time.Second):
+		t.Fatal("blocked Add did not complete after ready drained")
+	}
+}
@@ -0,0 +1,88 @@
+// oracle/consumer/eligibility.go
+package consumer
+
+import (
+	"strconv"
+	"time"
+
+	"oracle/config"
+	dbx "oracle/db"
+)
+
+// 제외 사유 (turn_audit.reason)
+const (
+	ExclNotRegistered    = "not_registered"
+	ExclAccountTooNew    = "account_too_new"
+	ExclEnergyBelowMin   = "energy_below_min"
+	ExclVoteOnlyDisabled = "vote_only_disabled"
+	ExclDenylisted       = "denylisted"
+	ExclEntityDuplicate  = "entity_duplicate"
+	ExclMissedRounds     = "missed_rounds"
+)
+
+// 가중치 계산 전에 평가하는 선언적 적격성 규칙
+type eligibilityRules struct {
+	RequireRegistered bool          `json:"require_registered"`
+	MinAccountAge     time.Duration `json:"min_account_age"`
+	MinEnergyKwh      float64       `json:"min_energy_kwh"` // 기여자(에너지 보고자)에만 적용
+	IncludeVoteOnly   bool          `json:"include_vote_only"`
+	UseDenylist       bool          `json:"use_denylist"`
+	UseMissPenalty    bool          `json:"use_miss_penalty"`
+}
+
+// 설정값으로 만든 현재 규칙.
+// 기본값은 규칙 도입 전 후보 집합을 그대로 둔다: 등록/계정 나이 규칙은 꺼져 있고,
+// 차단 목록은 비어 있으면 영향이 없으며, 미참여 제외는 PenaltyBanAfterMisses > 0 일 때만 적용된다.
+func currentEligibilityRules() eligibilityRules {
+	return eligibilityRules{
+		RequireRegistered: config.EligRequireRegistered,
+		MinAccountAge:     config.EligMinAccountAge,
+		MinEnergyKwh:      config.EligMinEnergyKwh,
+		IncludeVoteOnly:   config.EligIncludeVoteOnly,
+		UseDenylist:       config.EligUseDenylist,
+		UseMissPenalty:    config.EligUseMissPenalty && config.PenaltyBanAfterMisses > 0, // 제외 정책이 꺼져 있으면 원장 조회 생략
+	}
+}
+
+// 규칙이 계정 정보(DB)를 필요로 하는지
+func (r eligibilityRules) needsFacts() bool {
+	return r.RequireRegistered || r.MinAccountAge > 0 || r.UseDenylist || r.UseMissPenalty
+}
+
+// 후보별 규칙 평가. 통과한 후보와 제외 사유(address -> reason)를 반환.
+// 규칙은 아래 순서로 평가하며 처음 걸린 사유 하나만 기록한다.
+func applyEligibility(cands []Contributor, contribSet map[string]struct{}, facts map[string]dbx.AccountFacts, rules eligibilityRules, now time.Time) ([]Contributor, map[string]string) {
+	kept := make([]Contributor, 0, len(cands))
+	excluded := map[string]string{}
+
+	for _, c := range cands {
+		_, isContrib := contribSet[c.Address]
+		f := facts[c.Address]
+
+		reason := ""
+		switch {
+		case rules.UseDenylist && f.Denylisted:
+			reason = ExclDenylisted
+		case rules.UseMissPenalty && f.PenalizedUntil.After(now):
+			reason = ExclMissedRounds
+		case rules.RequireRegistered && !f.Registered:
+			reason = ExclNotRegistered
+		case rules.MinAccountAge > 0 && (!f.Registered || now.Sub(f.CreatedAt) < rules.MinAccountAge):
+			reason = ExclAccountTooNew
+		case !isContrib && !rules.IncludeVoteOnly:
+			reason = ExclVoteOnlyDisabled
+		case isContrib && rules.MinEnergyKwh > 0:
+			ekwh, _ := strconv.ParseFloat(c.EnergyKwh, 64)
+			if ekwh < rules.MinEnergyKwh {
+				reason = ExclEnergyBelowMin
+			}
+		}
+
+		if reason != "" {
+			excluded[c.Address] = reason
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept, excluded
+}
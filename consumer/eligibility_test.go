@@ -0,0 +1,126 @@
+package consumer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	dbx "oracle/db"
+)
+
+func TestApplyEligibility(t *testing.T) {
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	all := eligibilityRules{
+		RequireRegistered: true,
+		MinAccountAge:     24 * time.Hour,
+		MinEnergyKwh:      1,
+		IncludeVoteOnly:   false,
+		UseDenylist:       true,
+		UseMissPenalty:    true,
+	}
+	old := now.Add(-48 * time.Hour)
+
+	cases := []struct {
+		name    string
+		cand    Contributor
+		contrib bool
+		facts   dbx.AccountFacts
+		rules   eligibilityRules
+		want    string // "" = 통과
+	}{
+		{
+			name:    "denylist wins over every other rule",
+			cand:    Contributor{Address: "a", EnergyKwh: "0"},
+			contrib: true,
+			facts:   dbx.AccountFacts{Denylisted: true, PenalizedUntil: now.Add(time.Hour)},
+			rules:   all,
+			want:    ExclDenylisted,
+		},
+		{
+			name:    "miss penalty before registration",
+			cand:    Contributor{Address: "a", EnergyKwh: "5"},
+			contrib: true,
+			facts:   dbx.AccountFacts{PenalizedUntil: now.Add(time.Hour)},
+			rules:   all,
+			want:    ExclMissedRounds,
+		},
+		{
+			name:    "expired miss penalty ignored",
+			cand:    Contributor{Address: "a", EnergyKwh: "5"},
+			contrib: true,
+			facts:   dbx.AccountFacts{Registered: true, CreatedAt: old, PenalizedUntil: now},
+			rules:   all,
+		},
+		{
+			name:    "unregistered before account age",
+			cand:    Contributor{Address: "a", EnergyKwh: "5"},
+			contrib: true,
+			rules:   all,
+			want:    ExclNotRegistered,
+		},
+		{
+			name:    "account age applies to unregistered when registration not required",
+			cand:    Contributor{Address: "a", EnergyKwh: "5"},
+			contrib: true,
+			rules:   eligibilityRules{MinAccountAge: time.Hour},
+			want:    ExclAccountTooNew,
+		},
+		{
+			name:    "new account before vote-only and energy",
+			cand:    Contributor{Address: "a", EnergyKwh: "0"},
+			contrib: true,
+			facts:   dbx.AccountFacts{Registered: true, CreatedAt: now.Add(-time.Hour)},
+			rules:   all,
+			want:    ExclAccountTooNew,
+		},
+		{
+			name:  "vote-only disabled",
+			cand:  Contributor{Address: "v"},
+			facts: dbx.AccountFacts{Registered: true, CreatedAt: old},
+			rules: all,
+			want:  ExclVoteOnlyDisabled,
+		},
+		{
+			name:    "energy below minimum",
+			cand:    Contributor{Address: "a", EnergyKwh: "0.5"},
+			contrib: true,
+			facts:   dbx.AccountFacts{Registered: true, CreatedAt: old},
+			rules:   all,
+			want:    ExclEnergyBelowMin,
+		},
+		{
+			name:    "all rules passed",
+			cand:    Contributor{Address: "a", EnergyKwh: "1"},
+			contrib: true,
+			facts:   dbx.AccountFacts{Registered: true, CreatedAt: old},
+			rules:   all,
+		},
+		{
+			name:  "rules off keep everyone",
+			cand:  Contributor{Address: "v"},
+			facts: dbx.AccountFacts{Denylisted: true, PenalizedUntil: now.Add(time.Hour)},
+			rules: eligibilityRules{IncludeVoteOnly: true},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			set := map[string]struct{}{}
+			if tc.contrib {
+				set[tc.cand.Address] = struct{}{}
+			}
+			facts := map[string]dbx.AccountFacts{tc.cand.Address: tc.facts}
+			kept, excluded := applyEligibility([]Contributor{tc.cand}, set, facts, tc.rules, now)
+			if got := excluded[tc.cand.Address]; got != tc.want {
+				t.Fatalf("reason = %q, want %q", got, tc.want)
+			}
+			wantKept := []Contributor{}
+			if tc.want == "" {
+				wantKept = []Contributor{tc.cand}
+			}
+			if !reflect.DeepEqual(kept, wantKept) {
+				t.Fatalf("kept = %v, want %v", kept, wantKept)
+			}
+		})
+	}
+}
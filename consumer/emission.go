@@ -0,0 +1,113 @@
+// oracle/consumer/emission.go
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"oracle/config"
+	dbx "oracle/db"
+	"oracle/metrics"
+)
+
+const (
+	EmissionConstant = "constant" // 매 epoch 같은 예산
+	EmissionDecay    = "decay"    // B0 × (1 - rate)^epoch
+	EmissionHalving  = "halving"  // B0 / 2^(epoch / HalvingEpochs)
+)
+
+// 현재 epoch 예산 상태 (API 응답)
+type EmissionStatus struct {
+	On         bool      `json:"on"`
+	Schedule   string    `json:"schedule"`
+	Epoch      int64     `json:"epoch"`
+	EpochStart time.Time `json:"epoch_start"`
+	EpochEnd   time.Time `json:"epoch_end"`
+	Budget     float64   `json:"budget"`
+	Spent      float64   `json:"spent"`
+	Remaining  float64   `json:"remaining"`
+	Rounds     int       `json:"rounds"`
+}
+
+func emissionEpochLen() time.Duration {
+	if config.EmissionEpoch <= 0 {
+		return 24 * time.Hour
+	}
+	return config.EmissionEpoch
+}
+
+// t가 속한 epoch 번호 (genesis 이전은 0)
+func emissionEpochAt(t time.Time) int64 {
+	d := t.Sub(config.EmissionGenesis)
+	if d < 0 {
+		return 0
+	}
+	return int64(d / emissionEpochLen())
+}
+
+// epoch 예산 (스케줄 적용)
+func emissionBudget(epoch int64) float64 {
+	b0 := math.Max(0, config.EmissionBudget0)
+	switch config.EmissionSchedule {
+	case EmissionDecay:
+		rate := clamp(config.EmissionDecayRate, 0, 1)
+		return b0 * math.Pow(1-rate, float64(epoch))
+	case EmissionHalving:
+		if config.EmissionHalvingEpochs <= 0 {
+			return b0
+		}
+		return b0 / math.Pow(2, float64(epoch/int64(config.EmissionHalvingEpochs)))
+	default:
+		return b0
+	}
+}
+
+// 라운드에 epoch 예산 연결 (EmissionOn이 아니면 그대로)
+func withEmission(round dbx.RewardRound, now time.Time) dbx.RewardRound {
+	if !config.EmissionOn {
+		return round
+	}
+	e := emissionEpochAt(now)
+	round.EmissionEpoch = &e
+	round.EmissionBudget = emissionBudget(e)
+	return round
+}
+
+// 라운드 기록 후 메트릭 반영
+func observeEmission(round dbx.RewardRound) {
+	if round.EmissionEpoch == nil {
+		return
+	}
+	metrics.RewardEmissionBudgetGauge.Set(round.EmissionBudget)
+	metrics.RewardEmissionRemainingGauge.Set(math.Max(0, round.EmissionBudget-round.EmissionSpent-round.TotalReward))
+	if round.EmissionScale < 1 {
+		metrics.RewardEmissionScaledCounter.Inc()
+	}
+}
+
+// EmissionStatusAt : now가 속한 epoch의 예산/사용량/잔여
+func EmissionStatusAt(ctx context.Context, db *sql.DB, now time.Time) (EmissionStatus, error) {
+	e := emissionEpochAt(now)
+	start := config.EmissionGenesis.Add(time.Duration(e) * emissionEpochLen())
+	st := EmissionStatus{
+		On:         config.EmissionOn,
+		Schedule:   config.EmissionSchedule,
+		Epoch:      e,
+		EpochStart: start,
+		EpochEnd:   start.Add(emissionEpochLen()),
+		Budget:     emissionBudget(e),
+	}
+	spent, rounds, err := dbx.GetEmissionSpent(ctx, db, e)
+	if err != nil {
+		return st, err
+	}
+	st.Spent, st.Rounds = spent, rounds
+	st.Remaining = math.Max(0, st.Budget-spent)
+	if st.On {
+		metrics.RewardEmissionBudgetGauge.Set(st.Budget)
+		metrics.RewardEmissionRemainingGauge.Set(st.Remaining)
+	}
+	return st, nil
+}
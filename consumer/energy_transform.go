@@ -0,0 +1,92 @@
+// oracle/consumer/energy_transform.go
+package consumer
+
+import (
+	"math"
+	"sort"
+)
+
+// 에너지 항 변환 모드 (config.EnergyTransform)
+//   - "linear"   : t(e) = e                (기존 x_i = e_i/E)
+//   - "capacity" : t(e) = e / 설치용량(kW)  (kWh/kW, 이용률 기준)
+//   - "sqrt"     : t(e) = √e
+//   - "log"      : t(e) = ln(1+e)
+//   - "clip"     : t(e) = min(e, c)        (c = 고정값 또는 이번 턴 분위수)
+//
+// 모든 변환은 단조 증가라 더 많이 발전할수록 x_i가 커지는 인센티브는 유지된다.
+const (
+	EnergyLinear   = "linear"
+	EnergyCapacity = "capacity"
+	EnergySqrt     = "sqrt"
+	EnergyLog      = "log"
+	EnergyClip     = "clip"
+)
+
+type energyTransformOpts struct {
+	Mode            string
+	Capacities      map[string]float64 // address -> kW (capacity 모드)
+	DefaultCapacity float64            // 미등록 발전소 용량 (<=0 이면 원값 사용)
+	ClipKwh         float64            // clip 모드 고정 상한 (>0 이면 우선)
+	ClipQuantile    float64            // clip 모드 분위수 상한 (0<q<1)
+}
+
+// 주소별 e_i → t(e_i). 음수/NaN은 0으로 취급.
+func transformEnergy(energy map[string]float64, o energyTransformOpts) map[string]float64 {
+	out := make(map[string]float64, len(energy))
+
+	clip := math.Inf(1)
+	if o.Mode == EnergyClip {
+		clip = energyClipLevel(energy, o.ClipKwh, o.ClipQuantile)
+	}
+
+	for a, e := range energy {
+		if e <= 0 || math.IsNaN(e) || math.IsInf(e, 0) {
+			out[a] = 0
+			continue
+		}
+		var t float64
+		switch o.Mode {
+		case EnergyCapacity:
+			c, ok := o.Capacities[a]
+			if !ok || c <= 0 {
+				c = o.DefaultCapacity
+			}
+			if c > 0 {
+				t = e / c
+			} else {
+				t = e
+			}
+		case EnergySqrt:
+			t = math.Sqrt(e)
+		case EnergyLog:
+			t = math.Log1p(e)
+		case EnergyClip:
+			t = math.Min(e, clip)
+		default: // linear
+			t = e
+		}
+		out[a] = t
+	}
+	return out
+}
+
+// clip 상한: 고정값 우선, 없으면 이번 턴 양수 에너지의 분위수
+func energyClipLevel(energy map[string]float64, fixed, q float64) float64 {
+	if fixed > 0 {
+		return fixed
+	}
+	if q <= 0 || q >= 1 {
+		return math.Inf(1)
+	}
+	vals := make([]float64, 0, len(energy))
+	for _, e := range energy {
+		if e > 0 {
+			vals = append(vals, e)
+		}
+	}
+	if len(vals) == 0 {
+		return math.Inf(1)
+	}
+	sort.Float64s(vals)
+	return quantileSorted(vals, q)
+}
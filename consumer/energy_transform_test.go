@@ -0,0 +1,97 @@
+package consumer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTransformEnergy(t *testing.T) {
+	energy := map[string]float64{"a": 1, "b": 4, "c": 9, "neg": -2, "nan": math.NaN(), "zero": 0}
+	bad := map[string]float64{"neg": 0, "nan": 0, "zero": 0}
+	withBad := func(m map[string]float64) map[string]float64 {
+		for k, v := range bad {
+			m[k] = v
+		}
+		return m
+	}
+
+	cases := []struct {
+		name   string
+		energy map[string]float64
+		opts   energyTransformOpts
+		want   map[string]float64
+	}{
+		{
+			name:   "linear",
+			energy: energy,
+			opts:   energyTransformOpts{Mode: EnergyLinear},
+			want:   withBad(map[string]float64{"a": 1, "b": 4, "c": 9}),
+		},
+		{
+			name:   "unknown mode is linear",
+			energy: energy,
+			opts:   energyTransformOpts{Mode: "bogus"},
+			want:   withBad(map[string]float64{"a": 1, "b": 4, "c": 9}),
+		},
+		{
+			name:   "sqrt",
+			energy: energy,
+			opts:   energyTransformOpts{Mode: EnergySqrt},
+			want:   withBad(map[string]float64{"a": 1, "b": 2, "c": 3}),
+		},
+		{
+			name:   "log",
+			energy: energy,
+			opts:   energyTransformOpts{Mode: EnergyLog},
+			want:   withBad(map[string]float64{"a": math.Log(2), "b": math.Log(5), "c": math.Log(10)}),
+		},
+		{
+			name:   "capacity with registry, default and fallback",
+			energy: map[string]float64{"a": 10, "b": 10, "c": 10},
+			opts: energyTransformOpts{
+				Mode:            EnergyCapacity,
+				Capacities:      map[string]float64{"a": 5, "b": 0},
+				DefaultCapacity: 2,
+			},
+			want: map[string]float64{"a": 2, "b": 5, "c": 5},
+		},
+		{
+			name:   "capacity without default keeps raw energy",
+			energy: map[string]float64{"a": 10, "b": 7},
+			opts:   energyTransformOpts{Mode: EnergyCapacity, Capacities: map[string]float64{"a": 4}},
+			want:   map[string]float64{"a": 2.5, "b": 7},
+		},
+		{
+			name:   "clip fixed level wins over quantile",
+			energy: energy,
+			opts:   energyTransformOpts{Mode: EnergyClip, ClipKwh: 3, ClipQuantile: 0.1},
+			want:   withBad(map[string]float64{"a": 1, "b": 3, "c": 3}),
+		},
+		{
+			name:   "clip quantile of positive energy",
+			energy: map[string]float64{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "z": 0},
+			opts:   energyTransformOpts{Mode: EnergyClip, ClipQuantile: 0.5},
+			want:   map[string]float64{"a": 1, "b": 2, "c": 3, "d": 3, "e": 3, "z": 0},
+		},
+		{
+			name:   "clip without level is linear",
+			energy: map[string]float64{"a": 1, "b": 100},
+			opts:   energyTransformOpts{Mode: EnergyClip},
+			want:   map[string]float64{"a": 1, "b": 100},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := transformEnergy(tc.energy, tc.opts)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d entries, want %d", len(got), len(tc.want))
+			}
+			for a, w := range tc.want {
+				if math.Abs(got[a]-w) > 1e-12 {
+					t.Fatalf("t(%s) = %v, want %v", a, got[a], w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// oracle/consumer/entity_group.go
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strconv"
+
+	"github.com/lib/pq"
+)
+
+// 룰렛 후보 1행 (block_creator.go 루프와 헬퍼가 공유)
+type rouletteEntry struct {
+	addr string
+	w    float64
+	p    float64
+	f    float64
+
+	// 감사 기록용 (turn_audit)
+	wBase    float64 // 패널티 전 w_i
+	penalty  float64 // 공정성 패널티 계수 (1 = 없음)
+	penaltyR int     // 남은 패널티 턴수 R
+	pPre     float64 // P-cap 전 P_i
+
+	region    string  // 등록 위치 기반 권역
+	geoFactor float64 // 권역 다양성 보정 계수 (1 = 없음)
+
+	floorAdd float64 // 확률 하한 보정량 (+: 하한까지 올림, -: 재원으로 덜어냄)
+}
+
+type winStat struct {
+	WinsInWindow int
+	ExceedTurnID sql.NullInt64
+}
+
+// 그룹핑이 꺼져 있거나 조회 실패 시: 주소 = 엔티티
+func identityEntities(addrs []string) map[string]string {
+	out := make(map[string]string, len(addrs))
+	for _, a := range addrs {
+		out[a] = a
+	}
+	return out
+}
+
+// 같은 엔티티의 후보는 1개만 남긴다 (주소를 쪼개 등록해 ε·에너지 변환·P-cap에서 이득을 보지 못하도록).
+//   - 엔티티에 실제 기여자가 있으면 에너지가 가장 큰 기여자 주소를 대표로 남기고
+//     (동점이면 누적 점수, 그다음 주소 사전순) 엔티티 기여자 에너지 합을 대표 주소에 합산한다.
+//     vote-only 주소는 모두 제외
+//   - 기여자가 없으면 누적 점수가 가장 높은 주소 1개만 유지 (동점이면 주소 사전순)
+func dedupeByEntity(cands []Contributor, contribSet map[string]struct{}, entityOf map[string]string, scoreMap map[string]float64) []Contributor {
+	energyOf := func(c Contributor) float64 {
+		e, _ := strconv.ParseFloat(c.EnergyKwh, 64)
+		if e < 0 {
+			return 0
+		}
+		return e
+	}
+	better := func(a, b string, ea, eb float64) bool {
+		if ea != eb {
+			return ea > eb
+		}
+		if scoreMap[a] != scoreMap[b] {
+			return scoreMap[a] > scoreMap[b]
+		}
+		return a < b
+	}
+
+	hasContrib := map[string]bool{}
+	sumEnergy := map[string]float64{} // entity -> 기여자 에너지 합
+	for _, c := range cands {
+		if _, ok := contribSet[c.Address]; ok {
+			e := entityOf[c.Address]
+			hasContrib[e] = true
+			sumEnergy[e] += energyOf(c)
+		}
+	}
+	best := map[string]string{} // entity -> 대표 주소
+	bestE := map[string]float64{}
+	for _, c := range cands {
+		_, isContrib := contribSet[c.Address]
+		e := entityOf[c.Address]
+		if hasContrib[e] != isContrib {
+			continue
+		}
+		ce := 0.0
+		if isContrib {
+			ce = energyOf(c)
+		}
+		cur, ok := best[e]
+		if !ok || better(c.Address, cur, ce, bestE[e]) {
+			best[e], bestE[e] = c.Address, ce
+		}
+	}
+	out := make([]Contributor, 0, len(best))
+	for _, c := range cands {
+		e := entityOf[c.Address]
+		if best[e] != c.Address {
+			continue
+		}
+		if hasContrib[e] {
+			c.EnergyKwh = strconv.FormatFloat(sumEnergy[e], 'f', -1, 64)
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// 엔티티 단위 승리 통계를 주소 키로 펼쳐 반환.
+// entityOf에는 후보가 아닌 같은 엔티티 멤버도 포함될 수 있으며, 그들의 승리도 합산된다.
+func fetchEntityWinStatsForWindow(ctx context.Context, db *sql.DB, currentTurn int64, N, M int, addrs []string, entityOf map[string]string) (map[string]winStat, error) {
+	members := make([]string, 0, len(entityOf))
+	for a := range entityOf {
+		members = append(members, a)
+	}
+	rows, err := db.QueryContext(ctx, `
+SELECT creator, turn_id
+  FROM turn_result
+ WHERE turn_id > $1 - $2
+   AND NOT legacy_offset
+   AND creator = ANY($3)`, currentTurn, N, pq.Array(members))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	turnsByEntity := map[string][]int64{}
+	for rows.Next() {
+		var creator string
+		var turn int64
+		if err := rows.Scan(&creator, &turn); err != nil {
+			return nil, err
+		}
+		e, ok := entityOf[creator]
+		if !ok {
+			e = creator
+		}
+		turnsByEntity[e] = append(turnsByEntity[e], turn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	byEntity := make(map[string]winStat, len(turnsByEntity))
+	for e, turns := range turnsByEntity {
+		sort.Slice(turns, func(i, j int) bool { return turns[i] > turns[j] })
+		s := winStat{WinsInWindow: len(turns)}
+		// (M+1)번째 최신 승리 턴
+		if len(turns) > M {
+			s.WinsInWindow = M + 1
+			s.ExceedTurnID = sql.NullInt64{Int64: turns[M], Valid: true}
+		}
+		byEntity[e] = s
+	}
+
+	out := make(map[string]winStat, len(addrs))
+	for _, a := range addrs {
+		if s, ok := byEntity[entityOf[a]]; ok {
+			out[a] = s
+		}
+	}
+	return out, nil
+}
+
+// 엔티티 단위 P-cap: 엔티티 확률 합이 pcap을 넘으면 멤버를 비례 축소하고
+// 잔여 확률을 캡에 걸리지 않은 엔티티에 비례 재분배한다. 적용 여부를 반환.
+// 재분배로 다른 엔티티가 pcap을 넘으면 그 엔티티도 캡에 넣고 다시 나눈다
+// (엔티티 수 × pcap < 1 이라 전원이 캡에 걸리는 경우에는 마지막 엔티티들을 캡 없이 남긴다).
+func applyEntityPcap(ps []rouletteEntry, entityOf map[string]string, pcap float64) bool {
+	sumByEntity := map[string]float64{}
+	for _, r := range ps {
+		sumByEntity[entityOf[r.addr]] += r.p
+	}
+	capped := map[string]bool{}
+	var sumCapped, sumUncapped float64
+	for {
+		sumCapped, sumUncapped = float64(len(capped))*pcap, 0.0
+		for e, s := range sumByEntity {
+			if !capped[e] {
+				sumUncapped += s
+			}
+		}
+		var over []string
+		for e, s := range sumByEntity {
+			if capped[e] {
+				continue
+			}
+			scaled := s
+			if len(capped) > 0 && sumUncapped > 0 {
+				scaled = s / sumUncapped * (1.0 - sumCapped)
+			}
+			if scaled > pcap {
+				over = append(over, e)
+			}
+		}
+		if len(over) == 0 || len(capped)+len(over) >= len(sumByEntity) {
+			break
+		}
+		for _, e := range over {
+			capped[e] = true
+		}
+	}
+	if sumCapped == 0 || sumUncapped == 0 {
+		// 극단 케이스: 모두 Pcap 이하(또는 모두 초과)라면 아무 변화 없음
+		return false
+	}
+	leftover := 1.0 - sumCapped
+	for i := range ps {
+		e := entityOf[ps[i].addr]
+		if capped[e] {
+			ps[i].p = ps[i].p / sumByEntity[e] * pcap
+		} else {
+			ps[i].p = (ps[i].p / sumUncapped) * leftover
+		}
+	}
+	acc := 0.0
+	for i := range ps {
+		acc += ps[i].p
+		ps[i].f = acc
+	}
+	ps[len(ps)-1].f = 1.0
+	return true
+}
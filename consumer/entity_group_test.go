@@ -0,0 +1,156 @@
+package consumer
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestDedupeByEntity(t *testing.T) {
+	c := func(addr, kwh string) Contributor { return Contributor{Address: addr, EnergyKwh: kwh} }
+
+	cases := []struct {
+		name     string
+		cands    []Contributor
+		contribs []string
+		entityOf map[string]string
+		scores   map[string]float64
+		want     []Contributor
+	}{
+		{
+			name:     "separate entities untouched",
+			cands:    []Contributor{c("a", "1"), c("b", "2"), c("v", "")},
+			contribs: []string{"a", "b"},
+			entityOf: map[string]string{"a": "a", "b": "b", "v": "v"},
+			want:     []Contributor{c("a", "1"), c("b", "2"), c("v", "")},
+		},
+		{
+			name:     "contributors merged into largest producer",
+			cands:    []Contributor{c("a1", "3"), c("a2", "5"), c("a3", "-1"), c("b", "2")},
+			contribs: []string{"a1", "a2", "a3", "b"},
+			entityOf: map[string]string{"a1": "A", "a2": "A", "a3": "A", "b": "b"},
+			want:     []Contributor{c("a2", "8"), c("b", "2")},
+		},
+		{
+			name:     "vote-only dropped when entity has a contributor",
+			cands:    []Contributor{c("a1", "2"), c("v1", "")},
+			contribs: []string{"a1"},
+			entityOf: map[string]string{"a1": "A", "v1": "A"},
+			scores:   map[string]float64{"v1": 100},
+			want:     []Contributor{c("a1", "2")},
+		},
+		{
+			name:     "energy tie broken by score then address",
+			cands:    []Contributor{c("a2", "4"), c("a1", "4"), c("b2", "1"), c("b1", "1")},
+			contribs: []string{"a1", "a2", "b1", "b2"},
+			entityOf: map[string]string{"a1": "A", "a2": "A", "b1": "B", "b2": "B"},
+			scores:   map[string]float64{"a2": 3, "a1": 1},
+			want:     []Contributor{c("a2", "8"), c("b1", "2")},
+		},
+		{
+			name:     "vote-only entity keeps highest score",
+			cands:    []Contributor{c("v1", ""), c("v2", ""), c("v3", "")},
+			entityOf: map[string]string{"v1": "V", "v2": "V", "v3": "V"},
+			scores:   map[string]float64{"v1": 2, "v2": 5, "v3": 5},
+			want:     []Contributor{c("v2", "")},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			set := map[string]struct{}{}
+			for _, a := range tc.contribs {
+				set[a] = struct{}{}
+			}
+			got := dedupeByEntity(tc.cands, set, tc.entityOf, tc.scores)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyEntityPcap(t *testing.T) {
+	cases := []struct {
+		name     string
+		probs    map[string]float64
+		entityOf map[string]string
+		pcap     float64
+		applied  bool
+		want     map[string]float64
+	}{
+		{
+			name:     "nothing over cap",
+			probs:    map[string]float64{"a": 0.3, "b": 0.3, "c": 0.4},
+			entityOf: map[string]string{"a": "a", "b": "b", "c": "c"},
+			pcap:     0.5,
+			want:     map[string]float64{"a": 0.3, "b": 0.3, "c": 0.4},
+		},
+		{
+			name:     "entity sum capped, members scaled proportionally",
+			probs:    map[string]float64{"a1": 0.4, "a2": 0.3, "b": 0.2, "c": 0.1},
+			entityOf: map[string]string{"a1": "A", "a2": "A", "b": "b", "c": "c"},
+			pcap:     0.5,
+			applied:  true,
+			want:     map[string]float64{"a1": 0.4 / 0.7 * 0.5, "a2": 0.3 / 0.7 * 0.5, "b": 0.5 * 2 / 3, "c": 0.5 / 3},
+		},
+		{
+			name:     "redistribution pushes another entity over cap",
+			probs:    map[string]float64{"a": 0.7, "b1": 0.15, "b2": 0.1, "c": 0.05},
+			entityOf: map[string]string{"a": "A", "b1": "B", "b2": "B", "c": "c"},
+			pcap:     0.4,
+			applied:  true,
+			want:     map[string]float64{"a": 0.4, "b1": 0.24, "b2": 0.16, "c": 0.2},
+		},
+		{
+			name:     "every entity over cap is left unchanged",
+			probs:    map[string]float64{"a": 0.5, "b": 0.5},
+			entityOf: map[string]string{"a": "a", "b": "b"},
+			pcap:     0.4,
+			want:     map[string]float64{"a": 0.5, "b": 0.5},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ps := entriesFromProbs(tc.probs)
+			if got := applyEntityPcap(ps, tc.entityOf, tc.pcap); got != tc.applied {
+				t.Fatalf("applied = %v, want %v", got, tc.applied)
+			}
+			checkProbs(t, ps, tc.want)
+		})
+	}
+}
+
+// 주소 사전순으로 정렬된 후보 (selection.go와 같은 순서)
+func entriesFromProbs(probs map[string]float64) []rouletteEntry {
+	ps := make([]rouletteEntry, 0, len(probs))
+	for a, p := range probs {
+		ps = append(ps, rouletteEntry{addr: a, p: p})
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].addr < ps[j].addr })
+	acc := 0.0
+	for i := range ps {
+		acc += ps[i].p
+		ps[i].f = acc
+	}
+	return ps
+}
+
+func checkProbs(t *testing.T, ps []rouletteEntry, want map[string]float64) {
+	t.Helper()
+	sum := 0.0
+	for _, r := range ps {
+		if math.Abs(r.p-want[r.addr]) > 1e-9 {
+			t.Fatalf("p[%s] = %.12f, want %.12f", r.addr, r.p, want[r.addr])
+		}
+		sum += r.p
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("sum p = %.12f, want 1", sum)
+	}
+	if ps[len(ps)-1].f != 1.0 {
+		t.Fatalf("last F = %v, want 1", ps[len(ps)-1].f)
+	}
+}
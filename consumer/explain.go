@@ -0,0 +1,205 @@
+// oracle/consumer/explain.go
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	dbx "oracle/db"
+)
+
+// SelectionExplanation : 한 턴에서 한 주소의 선발 확률 분해 (turn_audit + turn_result.params 기반)
+type SelectionExplanation struct {
+	TurnID     int64  `json:"turn_id"`
+	Address    string `json:"address"`
+	EntityID   string `json:"entity_id"`
+	Winner     string `json:"winner"`
+	Candidates int    `json:"candidates"` // 적격 후보 수
+	Rank       int    `json:"rank"`       // 최종 P 기준 순위 (1 = 최고, 부적격이면 0)
+
+	// 적격성
+	Eligible bool   `json:"eligible"`
+	Reason   string `json:"reason,omitempty"`
+
+	// 에너지/투표 비중
+	EnergyKwh   float64 `json:"energy_kwh"`
+	EnergyShare float64 `json:"energy_share"` // x_i
+	VoteScore   float64 `json:"vote_score"`
+	VoteShare   float64 `json:"vote_share"` // r_i
+
+	// β 혼합: w_base = β·x_i + (1-β)·r_i + ε
+	Beta       float64 `json:"beta"`
+	EnergyTerm float64 `json:"energy_term"` // β·x_i
+	VoteTerm   float64 `json:"vote_term"`   // (1-β)·r_i
+	Eps        float64 `json:"eps"`
+	WBase      float64 `json:"w_base"`
+
+	// 공정성 패널티
+	Penalty   float64 `json:"penalty"`
+	PenaltyR  int     `json:"penalty_r"`
+	FairGamma float64 `json:"fair_gamma"`
+	FairMode  string  `json:"fair_mode"`
+	FairCapM  int     `json:"fair_cap_m"`
+	FairSoftK int     `json:"fair_soft_k"`
+
+	// 권역 다양성
+	Region    string  `json:"region,omitempty"`
+	GeoFactor float64 `json:"geo_factor"`
+
+	// 정규화, 확률 하한 및 P-cap
+	W             float64 `json:"w_i"`
+	WTotal        float64 `json:"w_total"`
+	PNormalized   float64 `json:"p_normalized"` // w_i / W
+	FloorMode     string  `json:"floor_mode,omitempty"`
+	PFloor        float64 `json:"p_floor"` // 하한 보정량 (+/-)
+	PPreCap       float64 `json:"p_pre_cap"`
+	PcapOn        bool    `json:"pcap_on"`
+	Pcap          float64 `json:"pcap"`
+	PcapTriggered bool    `json:"pcap_triggered"`
+	EntityPPreCap float64 `json:"entity_p_pre_cap"` // 같은 엔티티 P 합 (캡 판정 기준)
+	PcapEffect    string  `json:"pcap_effect"`      // "none" | "capped" | "redistributed"
+	P             float64 `json:"p_i"`
+
+	Notes []string `json:"notes"` // 사람이 읽는 요약
+}
+
+// 턴이 없거나 주소가 그 턴의 후보가 아니었을 때 (HTTP 404)
+var ErrExplainNotFound = errors.New("explain: not found")
+
+// ExplainSelection
+// - turnID의 감사 기록에서 address 행을 찾아 단계별 기여를 계산
+// - params가 없는 과거 턴은 β/패널티 설정 값이 0으로 남는다
+func ExplainSelection(ctx context.Context, db *sql.DB, turnID int64, address string) (SelectionExplanation, error) {
+	out := SelectionExplanation{TurnID: turnID, Address: address}
+
+	creator, raw, found, err := dbx.GetTurnParams(ctx, db, turnID)
+	if err != nil {
+		return out, err
+	}
+	if !found {
+		return out, fmt.Errorf("%w: turn %d not found", ErrExplainNotFound, turnID)
+	}
+	out.Winner = creator
+	var params turnParamsRecord
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return out, fmt.Errorf("turn %d params: %w", turnID, err)
+		}
+	}
+
+	rows, err := dbx.GetTurnAudit(ctx, db, turnID)
+	if err != nil {
+		return out, err
+	}
+	var me *dbx.TurnAuditRow
+	eligible := make([]dbx.TurnAuditRow, 0, len(rows))
+	entitySum := map[string]float64{}
+	for i := range rows {
+		if rows[i].Address == address {
+			me = &rows[i]
+		}
+		if rows[i].Eligible {
+			eligible = append(eligible, rows[i])
+			out.WTotal += rows[i].W
+			entitySum[rows[i].EntityID] += rows[i].PPreCap
+		}
+	}
+	if me == nil {
+		return out, fmt.Errorf("%w: address %s was not a candidate in turn %d", ErrExplainNotFound, address, turnID)
+	}
+	out.Candidates = len(eligible)
+
+	out.EntityID = me.EntityID
+	out.Eligible, out.Reason = me.Eligible, me.Reason
+	out.EnergyKwh, out.EnergyShare = me.EnergyKwh, me.X
+	out.VoteScore, out.VoteShare = me.VoteScore, me.R
+	out.Beta, out.Eps = params.Beta, params.Eps
+	out.EnergyTerm = params.Beta * me.X
+	out.VoteTerm = (1 - params.Beta) * me.R
+	out.WBase = me.WBase
+	out.Penalty, out.PenaltyR = me.Penalty, me.PenaltyR
+	out.FairGamma, out.FairMode = params.FairGamma, params.FairMode
+	out.FairCapM, out.FairSoftK = params.FairCapM, params.FairSoftK
+	out.Region, out.GeoFactor = me.Region, me.GeoFactor
+	out.W, out.PPreCap, out.P = me.W, me.PPreCap, me.P
+	out.PNormalized = me.PPreCap - me.PFloor
+	out.FloorMode, out.PFloor = params.FloorMode, me.PFloor
+	out.PcapOn, out.Pcap, out.PcapTriggered = params.PcapOn, params.Pcap, params.PcapTriggered
+	out.EntityPPreCap = entitySum[me.EntityID]
+	out.PcapEffect = "none"
+	if me.Eligible && params.PcapTriggered {
+		if out.EntityPPreCap > params.Pcap {
+			out.PcapEffect = "capped"
+		} else {
+			out.PcapEffect = "redistributed"
+		}
+	}
+
+	if me.Eligible {
+		sort.SliceStable(eligible, func(i, j int) bool { return eligible[i].P > eligible[j].P })
+		for i, r := range eligible {
+			if r.Address == address {
+				out.Rank = i + 1
+				break
+			}
+		}
+	}
+	out.Notes = explainNotes(out)
+	return out, nil
+}
+
+func explainNotes(e SelectionExplanation) []string {
+	var n []string
+	if !e.Eligible {
+		return append(n, fmt.Sprintf("excluded before weighting: %s", e.Reason))
+	}
+	uniform := 0.0
+	if e.Candidates > 0 {
+		uniform = 1 / float64(e.Candidates)
+	}
+	n = append(n, fmt.Sprintf("energy share x=%.6f (%.4f kWh), vote share r=%.6f (score %.4f)",
+		e.EnergyShare, e.EnergyKwh, e.VoteShare, e.VoteScore))
+	n = append(n, fmt.Sprintf("beta=%.3f mix: %.6f (energy) + %.6f (vote) + eps = w_base %.6f",
+		e.Beta, e.EnergyTerm, e.VoteTerm, e.WBase))
+	if e.PenaltyR > 0 {
+		n = append(n, fmt.Sprintf("fairness penalty x%.4f (%s, gamma=%.3f, %d turn(s) remaining; more than %d wins in window)",
+			e.Penalty, e.FairMode, e.FairGamma, e.PenaltyR, e.FairCapM))
+	}
+	if e.GeoFactor != 1 {
+		n = append(n, fmt.Sprintf("region %q diversity factor x%.4f", e.Region, e.GeoFactor))
+	}
+	n = append(n, fmt.Sprintf("normalized: w=%.6f / W=%.6f = %.6f", e.W, e.WTotal, e.PNormalized))
+	if e.PFloor > 0 {
+		n = append(n, fmt.Sprintf("probability floor (%s) raised P by %.6f to %.6f before P-cap", e.FloorMode, e.PFloor, e.PPreCap))
+	} else if e.PFloor < 0 {
+		n = append(n, fmt.Sprintf("probability floor (%s) funded by this candidate: %.6f -> %.6f before P-cap", e.FloorMode, e.PNormalized, e.PPreCap))
+	}
+	switch e.PcapEffect {
+	case "capped":
+		n = append(n, fmt.Sprintf("P-cap %.3f: entity total %.6f exceeded the cap, scaled down to %.6f",
+			e.Pcap, e.EntityPPreCap, e.P))
+	case "redistributed":
+		n = append(n, fmt.Sprintf("P-cap %.3f on other entities: received redistributed probability %.6f -> %.6f",
+			e.Pcap, e.PPreCap, e.P))
+	}
+	n = append(n, fmt.Sprintf("final P=%.6f (rank %d of %d, uniform would be %.6f)", e.P, e.Rank, e.Candidates, uniform))
+	if e.Winner == e.Address {
+		n = append(n, "selected as block creator this turn")
+	}
+	return n
+}
+
+// CLI/로그용 텍스트 출력
+func (e SelectionExplanation) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "turn=%d address=%s entity=%s winner=%s\n", e.TurnID, e.Address, e.EntityID, e.Winner)
+	for _, line := range e.Notes {
+		fmt.Fprintf(&b, "  - %s\n", line)
+	}
+	return b.String()
+}
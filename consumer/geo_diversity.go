@@ -0,0 +1,124 @@
+// oracle/consumer/geo_diversity.go
+package consumer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// 권역 다양성 선발 모드 (config.GeoDiversityMode)
+//   - "off"      : 적용 안 함
+//   - "cap"      : 최근 N턴 승리 중 특정 권역 비중이 상한 이상이면 그 권역 후보 가중치를 GeoCapFactor배
+//   - "stratify" : 권역별 가중치 합을 균등하게 맞춘 뒤(권역 1/R) 권역 안에서는 기존 비율 유지
+const (
+	GeoOff      = "off"
+	GeoCap      = "cap"
+	GeoStratify = "stratify"
+)
+
+const unknownRegion = "기타/미분류"
+
+// 등록 위치 → 권역 (station_region.go와 같은 bucketRegion 규칙)
+func regionsOf(addrs []string, locations map[string]string) map[string]string {
+	out := make(map[string]string, len(addrs))
+	for _, a := range addrs {
+		out[a] = deriveRegion(locations[a], "")
+	}
+	return out
+}
+
+// 최근 승자 위치 목록 → 권역별 승리 비중
+func regionWinShares(winnerLocations []string) map[string]float64 {
+	out := map[string]float64{}
+	if len(winnerLocations) == 0 {
+		return out
+	}
+	for _, loc := range winnerLocations {
+		out[deriveRegion(loc, "")]++
+	}
+	for r := range out {
+		out[r] /= float64(len(winnerLocations))
+	}
+	return out
+}
+
+// ps[i].w 에 권역 보정을 곱하고 geoFactor를 기록. 새 W를 반환.
+// 보정 후 모든 가중치가 0이 되면 보정을 되돌린다.
+func applyGeoDiversity(ps []rouletteEntry, regionOf map[string]string, mode string, shares map[string]float64, capShare, capFactor float64) float64 {
+	orig := make([]float64, len(ps))
+	for i := range ps {
+		orig[i] = ps[i].w
+		ps[i].region = regionOf[ps[i].addr]
+		ps[i].geoFactor = 1
+	}
+
+	switch mode {
+	case GeoCap:
+		capped := []string{}
+		for r, sh := range shares {
+			if r != unknownRegion && sh >= capShare {
+				capped = append(capped, r)
+			}
+		}
+		if len(capped) == 0 {
+			break
+		}
+		sort.Strings(capped)
+		isCapped := map[string]bool{}
+		for _, r := range capped {
+			isCapped[r] = true
+		}
+		for i := range ps {
+			if isCapped[ps[i].region] {
+				ps[i].geoFactor = capFactor
+				ps[i].w *= capFactor
+			}
+		}
+		fmt.Printf("[Geo] cap: regions over %.2f win share → factor %.3f: %v\n", capShare, capFactor, capped)
+
+	case GeoStratify:
+		sumByRegion := map[string]float64{}
+		var W float64
+		for _, r := range ps {
+			sumByRegion[r.region] += r.w
+			W += r.w
+		}
+		nonEmpty := 0
+		for _, s := range sumByRegion {
+			if s > 0 {
+				nonEmpty++
+			}
+		}
+		if nonEmpty <= 1 {
+			break
+		}
+		share := W / float64(nonEmpty)
+		for i := range ps {
+			s := sumByRegion[ps[i].region]
+			if s <= 0 {
+				continue
+			}
+			ps[i].geoFactor = share / s
+			ps[i].w *= ps[i].geoFactor
+		}
+	}
+
+	var W float64
+	for i := range ps {
+		if ps[i].w < 0 || math.IsNaN(ps[i].w) || math.IsInf(ps[i].w, 0) {
+			ps[i].w = 0
+		}
+		W += ps[i].w
+	}
+	if W <= 0 {
+		fmt.Println("[Geo] note: all-zero after region adjustment -> revert")
+		W = 0
+		for i := range ps {
+			ps[i].w = orig[i]
+			ps[i].geoFactor = 1
+			W += ps[i].w
+		}
+	}
+	return W
+}
@@ -0,0 +1,423 @@
+// oracle/consumer/governance.go
+package consumer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"oracle/config"
+	dbx "oracle/db"
+)
+
+// GovParams : 거버넌스로 바꿀 수 있는 파라미터 (비어 있는 항목은 그대로)
+type GovParams struct {
+	// 블록 생성자 선발
+	Beta        *float64 `json:"beta,omitempty"`
+	FairOn      *bool    `json:"fair_on,omitempty"`
+	FairWindowN *int     `json:"fair_window_n,omitempty"`
+	FairCapM    *int     `json:"fair_cap_m,omitempty"`
+	FairSoftK   *int     `json:"fair_soft_k,omitempty"`
+	FairGamma   *float64 `json:"fair_gamma,omitempty"`
+	FairMode    *string  `json:"fair_mode,omitempty"`
+	PcapOn      *bool    `json:"pcap_on,omitempty"`
+	Pcap        *float64 `json:"pcap,omitempty"`
+	FloorMode   *string  `json:"floor_mode,omitempty"`
+	FloorValue  *float64 `json:"floor_value,omitempty"`
+	FloorBudget *float64 `json:"floor_budget,omitempty"`
+
+	// 서명자 보상 정책
+	RewardBeta           *float64 `json:"reward_beta,omitempty"`
+	RewardRStart         *float64 `json:"reward_r_start,omitempty"`
+	RewardInactivityDays *int     `json:"reward_inactivity_days,omitempty"`
+}
+
+func (g GovParams) validate() error {
+	sel := WhatIfRequest{Beta: g.Beta, FairOn: g.FairOn, FairWindowN: g.FairWindowN, FairCapM: g.FairCapM,
+		FairSoftK: g.FairSoftK, FairGamma: g.FairGamma, FairMode: g.FairMode, PcapOn: g.PcapOn, Pcap: g.Pcap}
+	if err := sel.validate(); err != nil {
+		return err
+	}
+	if g.FloorMode != nil && *g.FloorMode != FloorOff && *g.FloorMode != FloorAbsolute && *g.FloorMode != FloorUniform {
+		return fmt.Errorf("floor_mode must be off, absolute or uniform")
+	}
+	if (g.FloorValue != nil && (*g.FloorValue < 0 || *g.FloorValue > 1)) ||
+		(g.FloorBudget != nil && (*g.FloorBudget < 0 || *g.FloorBudget > 1)) {
+		return fmt.Errorf("floor_value/floor_budget must be in [0,1]")
+	}
+	if (g.RewardBeta != nil && *g.RewardBeta < 0) ||
+		(g.RewardRStart != nil && (*g.RewardRStart < 0 || *g.RewardRStart >= 1)) ||
+		(g.RewardInactivityDays != nil && *g.RewardInactivityDays < 1) {
+		return fmt.Errorf("reward policy values out of range")
+	}
+	return nil
+}
+
+func (g GovParams) applyTurn(p turnParamsRecord) turnParamsRecord {
+	p = WhatIfRequest{Beta: g.Beta, FairOn: g.FairOn, FairWindowN: g.FairWindowN, FairCapM: g.FairCapM,
+		FairSoftK: g.FairSoftK, FairGamma: g.FairGamma, FairMode: g.FairMode, PcapOn: g.PcapOn, Pcap: g.Pcap}.apply(p)
+	if g.FloorMode != nil {
+		p.FloorMode = *g.FloorMode
+	}
+	if g.FloorValue != nil {
+		p.FloorValue = *g.FloorValue
+	}
+	if g.FloorBudget != nil {
+		p.FloorBudget = *g.FloorBudget
+	}
+	return p
+}
+
+func (g GovParams) applyPolicy(pol Policy) Policy {
+	if g.RewardBeta != nil {
+		pol.Beta = *g.RewardBeta
+	}
+	if g.RewardRStart != nil {
+		pol.RStart = *g.RewardRStart
+	}
+	if g.RewardInactivityDays != nil {
+		pol.InactivityDays = *g.RewardInactivityDays
+	}
+	return pol
+}
+
+// ---- Kafka 메시지 ----
+
+// GovProposalMsg : 풀노드 제안. Signature = ed25519(proposalSigningBytes), hex 또는 base64
+type GovProposalMsg struct {
+	FullnodeID   string          `json:"fullnode_id"`
+	Params       json.RawMessage `json:"params"`
+	ActivateTurn int64           `json:"activate_turn"`
+	Nonce        string          `json:"nonce"`
+	Signature    string          `json:"signature"`
+}
+
+// GovVoteMsg : 풀노드 투표. Signature = ed25519(voteSigningBytes)
+type GovVoteMsg struct {
+	FullnodeID string `json:"fullnode_id"`
+	ProposalID string `json:"proposal_id"`
+	Approve    bool   `json:"approve"`
+	Signature  string `json:"signature"`
+}
+
+// 서명 대상: 줄 단위 고정 형식, params는 키 정렬 JSON
+func proposalSigningBytes(fullnodeID string, activateTurn int64, nonce string, canonicalParams []byte) []byte {
+	return []byte(fmt.Sprintf("oracle-gov/proposal\n%s\n%d\n%s\n%s", fullnodeID, activateTurn, nonce, canonicalParams))
+}
+
+func voteSigningBytes(fullnodeID, proposalID string, approve bool) []byte {
+	return []byte(fmt.Sprintf("oracle-gov/vote\n%s\n%s\n%t", fullnodeID, proposalID, approve))
+}
+
+// 키 정렬/공백 제거
+func canonicalParamsJSON(raw []byte) ([]byte, error) {
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+func decodeKeyMaterial(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if b, err := hex.DecodeString(strings.TrimPrefix(s, "0x")); err == nil {
+		return b, nil
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// fullnode_registry의 active 풀노드 공개키로 ed25519 서명 검증
+func verifyFullnodeSignature(ctx context.Context, db *sql.DB, fullnodeID string, msg []byte, sig string) error {
+	f, ok, err := dbx.GetFullnode(ctx, db, fullnodeID)
+	if err != nil {
+		return err
+	}
+	if !ok || !f.Active {
+		return fmt.Errorf("fullnode %s is not an active registered fullnode", fullnodeID)
+	}
+	pub, err := decodeKeyMaterial(f.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("fullnode %s has no valid ed25519 public key", fullnodeID)
+	}
+	s, err := decodeKeyMaterial(sig)
+	if err != nil || len(s) != ed25519.SignatureSize {
+		return fmt.Errorf("malformed signature")
+	}
+	if !ed25519.Verify(pub, msg, s) {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
+
+// ---- 승인된 파라미터 오버레이 ----
+
+type govActivation struct {
+	ProposalID   string
+	ActivateTurn int64
+	Params       GovParams
+}
+
+type govOverlay struct {
+	mu       sync.RWMutex
+	approved []govActivation // activate_turn 순
+}
+
+var governance = &govOverlay{}
+
+func (o *govOverlay) Reload(ctx context.Context, db *sql.DB) error {
+	list, err := dbx.ListApprovedGovProposals(ctx, db)
+	if err != nil {
+		return err
+	}
+	acts := make([]govActivation, 0, len(list))
+	for _, p := range list {
+		var g GovParams
+		if err := json.Unmarshal(p.Params, &g); err != nil {
+			fmt.Printf("[Gov] proposal=%s params unreadable; skipped: %v\n", p.ProposalID, err)
+			continue
+		}
+		acts = append(acts, govActivation{ProposalID: p.ProposalID, ActivateTurn: p.ActivateTurn, Params: g})
+	}
+	o.mu.Lock()
+	o.approved = acts
+	o.mu.Unlock()
+	return nil
+}
+
+// turnID 시점에 활성화된 제안을 순서대로 덮어쓴 선발 파라미터
+func (o *govOverlay) TurnParams(p turnParamsRecord, turnID int64) turnParamsRecord {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	for _, a := range o.approved {
+		if a.ActivateTurn > turnID {
+			break
+		}
+		p = a.Params.applyTurn(p)
+		p.Governance = append(p.Governance, a.ProposalID)
+	}
+	return p
+}
+
+// turnID 시점에 활성화된 제안을 덮어쓴 보상 정책
+func (o *govOverlay) Policy(pol Policy, turnID int64) Policy {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	for _, a := range o.approved {
+		if a.ActivateTurn > turnID {
+			break
+		}
+		pol = a.Params.applyPolicy(pol)
+		pol.Governance = append(pol.Governance, a.ProposalID)
+	}
+	return pol
+}
+
+// 보상 라운드는 턴이 없으므로 마지막 결정 턴 기준
+func governedRewardPolicy(db *sql.DB) Policy {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	last, _, err := dbx.LatestTurnID(ctx, db)
+	if err != nil {
+		fmt.Println("[Gov] latest turn lookup failed (default policy):", err)
+		return DefaultPolicy()
+	}
+	return governance.Policy(DefaultPolicy(), last)
+}
+
+// ---- 제안/투표 처리 ----
+
+func handleGovProposal(ctx context.Context, db *sql.DB, m GovProposalMsg) error {
+	dec := json.NewDecoder(bytes.NewReader(m.Params))
+	dec.DisallowUnknownFields()
+	var g GovParams
+	if err := dec.Decode(&g); err != nil {
+		return fmt.Errorf("params: %w", err)
+	}
+	if err := g.validate(); err != nil {
+		return err
+	}
+	canon, err := canonicalParamsJSON(m.Params)
+	if err != nil {
+		return fmt.Errorf("params: %w", err)
+	}
+	signed := proposalSigningBytes(m.FullnodeID, m.ActivateTurn, m.Nonce, canon)
+	if err := verifyFullnodeSignature(ctx, db, m.FullnodeID, signed, m.Signature); err != nil {
+		return err
+	}
+	last, _, err := dbx.LatestTurnID(ctx, db)
+	if err != nil {
+		return err
+	}
+	if m.ActivateTurn < last+int64(config.GovMinActivationDelay) {
+		return fmt.Errorf("activate_turn %d must be at least %d turns after latest turn %d",
+			m.ActivateTurn, config.GovMinActivationDelay, last)
+	}
+
+	sum := sha256.Sum256(signed)
+	id := hex.EncodeToString(sum[:])
+	inserted, err := dbx.InsertGovProposal(ctx, db, dbx.GovProposal{
+		ProposalID:   id,
+		Proposer:     m.FullnodeID,
+		Params:       canon,
+		ActivateTurn: m.ActivateTurn,
+		Nonce:        m.Nonce,
+		Signature:    m.Signature,
+	})
+	if err != nil || !inserted {
+		return err
+	}
+	fmt.Printf("[Gov] proposal=%s by=%s activate_turn=%d params=%s\n", id, m.FullnodeID, m.ActivateTurn, canon)
+	return nil
+}
+
+func handleGovVote(ctx context.Context, db *sql.DB, m GovVoteMsg) error {
+	p, ok, err := dbx.GetGovProposal(ctx, db, m.ProposalID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("unknown proposal %s", m.ProposalID)
+	}
+	if p.Status != "open" {
+		return fmt.Errorf("proposal %s is %s", p.ProposalID, p.Status)
+	}
+	if err := verifyFullnodeSignature(ctx, db, m.FullnodeID, voteSigningBytes(m.FullnodeID, m.ProposalID, m.Approve), m.Signature); err != nil {
+		return err
+	}
+	last, _, err := dbx.LatestTurnID(ctx, db)
+	if err != nil {
+		return err
+	}
+	if last >= p.ActivateTurn {
+		_, err := dbx.DecideGovProposal(ctx, db, p.ProposalID, "expired")
+		fmt.Printf("[Gov] proposal=%s expired before quorum (latest turn %d >= %d)\n", p.ProposalID, last, p.ActivateTurn)
+		return err
+	}
+	if _, err := dbx.InsertGovVote(ctx, db, dbx.GovVote{
+		ProposalID: m.ProposalID, FullnodeID: m.FullnodeID, Approve: m.Approve, Signature: m.Signature,
+	}); err != nil {
+		return err
+	}
+
+	approve, reject, active, err := dbx.TallyGovVotes(ctx, db, p.ProposalID)
+	if err != nil {
+		return err
+	}
+	need := int(math.Ceil(config.GovQuorumFrac * float64(active)))
+	if need < 1 {
+		need = 1
+	}
+	fmt.Printf("[Gov] proposal=%s vote by=%s approve=%t tally=%d/%d (reject %d, active %d)\n",
+		p.ProposalID, m.FullnodeID, m.Approve, approve, need, reject, active)
+	switch {
+	case approve >= need:
+		if ok, err := dbx.DecideGovProposal(ctx, db, p.ProposalID, "approved"); err != nil || !ok {
+			return err
+		}
+		fmt.Printf("[Gov] proposal=%s approved; activates at turn %d\n", p.ProposalID, p.ActivateTurn)
+		return governance.Reload(ctx, db)
+	case active-reject < need:
+		_, err := dbx.DecideGovProposal(ctx, db, p.ProposalID, "rejected")
+		fmt.Printf("[Gov] proposal=%s rejected\n", p.ProposalID)
+		return err
+	}
+	return nil
+}
+
+// StartGovernanceConsumer
+// - TopicGovProposal / TopicGovVote 구독
+// - 서명은 fullnode_registry(active)의 ed25519 공개키로 검증
+// - 승인 비율 GovQuorumFrac(active 풀노드 기준) 도달 시 activate_turn부터 적용
+func StartGovernanceConsumer(db *sql.DB) error {
+	ctxInit, cancelInit := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := governance.Reload(ctxInit, db); err != nil {
+		fmt.Println("[Gov] initial reload failed:", err)
+	}
+	cancelInit()
+
+	cfg := sarama.NewConfig()
+	cfg.Version = sarama.V2_1_0_0
+	cons, err := sarama.NewConsumer(config.KafkaBrokers, cfg)
+	if err != nil {
+		return err
+	}
+	const partition = int32(0)
+	pcProp, err := cons.ConsumePartition(config.TopicGovProposal, partition, sarama.OffsetNewest)
+	if err != nil {
+		_ = cons.Close()
+		return err
+	}
+	pcVote, err := cons.ConsumePartition(config.TopicGovVote, partition, sarama.OffsetNewest)
+	if err != nil {
+		_ = pcProp.Close()
+		_ = cons.Close()
+		return err
+	}
+
+	go func() {
+		defer func() { _ = pcProp.Close(); _ = pcVote.Close(); _ = cons.Close() }()
+		for {
+			select {
+			case m, ok := <-pcProp.Messages():
+				if !ok {
+					return
+				}
+				var msg GovProposalMsg
+				if err := json.Unmarshal(m.Value, &msg); err != nil {
+					fmt.Println("[Gov] proposal parse failed:", err)
+					continue
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := handleGovProposal(ctx, db, msg); err != nil {
+					fmt.Printf("[Gov] proposal from %s rejected: %v\n", msg.FullnodeID, err)
+				}
+				cancel()
+			case m, ok := <-pcVote.Messages():
+				if !ok {
+					return
+				}
+				var msg GovVoteMsg
+				if err := json.Unmarshal(m.Value, &msg); err != nil {
+					fmt.Println("[Gov] vote parse failed:", err)
+					continue
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := handleGovVote(ctx, db, msg); err != nil {
+					fmt.Printf("[Gov] vote from %s rejected: %v\n", msg.FullnodeID, err)
+				}
+				cancel()
+			}
+		}
+	}()
+	return nil
+}
+
+// 거버넌스 이력 (API 응답용, params 포함)
+type GovProposalView struct {
+	dbx.GovProposal
+	Params json.RawMessage `json:"params"`
+}
+
+func GovernanceHistory(ctx context.Context, db *sql.DB, limit int) ([]GovProposalView, error) {
+	list, err := dbx.ListGovHistory(ctx, db, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]GovProposalView, 0, len(list))
+	for _, p := range list {
+		out = append(out, GovProposalView{GovProposal: p, Params: json.RawMessage(p.Params)})
+	}
+	return out, nil
+}
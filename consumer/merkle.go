@@ -0,0 +1,98 @@
+// oracle/consumer/merkle.go
+package consumer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Merkle 트리 (RFC 6962 방식 도메인 분리)
+// - 잎  : sha256(0x00 || kind ":" ref ":" payload)
+// - 노드: sha256(0x01 || left || right)
+// - 홀수 개 레벨의 마지막 노드는 짝 없이 위로 올라간다
+
+func merkleLeafHash(kind, ref, payload string) string {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write([]byte(kind + ":" + ref + ":" + payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func merkleNode(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// 증명 1단계: 형제 해시와 그 위치
+type MerkleStep struct {
+	Sibling string `json:"sibling"`
+	Left    bool   `json:"left"` // true면 형제가 왼쪽
+}
+
+func decodeLeaves(leaves []string) ([][]byte, error) {
+	level := make([][]byte, len(leaves))
+	for i, l := range leaves {
+		b, err := hex.DecodeString(l)
+		if err != nil {
+			return nil, fmt.Errorf("leaf %d: %w", i, err)
+		}
+		level[i] = b
+	}
+	return level, nil
+}
+
+// 잎 해시(hex) 목록의 root와 index 잎의 포함 증명 (index < 0 이면 증명 생략)
+func merkleRootAndProof(leaves []string, index int) (string, []MerkleStep, error) {
+	if len(leaves) == 0 {
+		return "", nil, fmt.Errorf("empty tree")
+	}
+	level, err := decodeLeaves(leaves)
+	if err != nil {
+		return "", nil, err
+	}
+	var proof []MerkleStep
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			if index == i {
+				proof = append(proof, MerkleStep{Sibling: hex.EncodeToString(level[i+1])})
+			} else if index == i+1 {
+				proof = append(proof, MerkleStep{Sibling: hex.EncodeToString(level[i]), Left: true})
+			}
+			next = append(next, merkleNode(level[i], level[i+1]))
+		}
+		if index >= 0 {
+			index /= 2
+		}
+		level = next
+	}
+	return hex.EncodeToString(level[0]), proof, nil
+}
+
+// 증명 검증: 잎에서 root까지 다시 계산
+func verifyMerkleProof(leaf string, proof []MerkleStep, root string) bool {
+	cur, err := hex.DecodeString(leaf)
+	if err != nil {
+		return false
+	}
+	for _, s := range proof {
+		sib, err := hex.DecodeString(s.Sibling)
+		if err != nil {
+			return false
+		}
+		if s.Left {
+			cur = merkleNode(sib, cur)
+		} else {
+			cur = merkleNode(cur, sib)
+		}
+	}
+	return hex.EncodeToString(cur) == root
+}
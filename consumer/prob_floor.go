@@ -0,0 +1,70 @@
+// oracle/consumer/prob_floor.go
+package consumer
+
+const (
+	FloorOff      = "off"
+	FloorAbsolute = "absolute" // P_i >= ProbFloorValue
+	FloorUniform  = "uniform"  // P_i >= ProbFloorValue × (1/n)
+)
+
+// 후보 수 n에서의 하한 값 (0 = 미적용)
+func probFloorLevel(mode string, value float64, n int) float64 {
+	if n <= 0 || value <= 0 {
+		return 0
+	}
+	switch mode {
+	case FloorAbsolute:
+		return value
+	case FloorUniform:
+		return value / float64(n)
+	default:
+		return 0
+	}
+}
+
+// applyProbFloor
+// - 하한 미만 후보를 하한까지 올리고, 그 확률을 하한 초과분에 비례해 다른 후보에서 덜어낸다
+// - 올려 주는 총량은 budget으로 제한(초과 시 부족분을 비례 축소), 덜어낼 여유보다 클 수 없다
+// - 후보별 증가량은 ps[i].floorAdd, 사용한 총량을 반환. F_i 재계산 포함
+func applyProbFloor(ps []rouletteEntry, floor, budget float64) float64 {
+	if floor <= 0 || len(ps) == 0 {
+		return 0
+	}
+	var deficit, excess float64
+	for _, r := range ps {
+		if r.p < floor {
+			deficit += floor - r.p
+		} else {
+			excess += r.p - floor
+		}
+	}
+	if deficit <= 0 || excess <= 0 {
+		return 0
+	}
+	used := deficit
+	if budget > 0 && used > budget {
+		used = budget
+	}
+	if used > excess {
+		used = excess
+	}
+	scale := used / deficit
+	take := used / excess
+	for i := range ps {
+		if ps[i].p < floor {
+			ps[i].floorAdd = (floor - ps[i].p) * scale
+			ps[i].p += ps[i].floorAdd
+		} else {
+			d := (ps[i].p - floor) * take
+			ps[i].floorAdd = -d
+			ps[i].p -= d
+		}
+	}
+	acc := 0.0
+	for i := range ps {
+		acc += ps[i].p
+		ps[i].f = acc
+	}
+	ps[len(ps)-1].f = 1.0
+	return used
+}
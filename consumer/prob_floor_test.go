@@ -0,0 +1,96 @@
+package consumer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProbFloorLevel(t *testing.T) {
+	cases := []struct {
+		mode  string
+		value float64
+		n     int
+		want  float64
+	}{
+		{FloorOff, 0.1, 10, 0},
+		{FloorAbsolute, 0.02, 10, 0.02},
+		{FloorUniform, 0.5, 10, 0.05},
+		{FloorUniform, 0.5, 0, 0},
+		{FloorAbsolute, 0, 10, 0},
+	}
+	for _, tc := range cases {
+		if got := probFloorLevel(tc.mode, tc.value, tc.n); math.Abs(got-tc.want) > 1e-12 {
+			t.Fatalf("probFloorLevel(%s, %v, %d) = %v, want %v", tc.mode, tc.value, tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestApplyProbFloor(t *testing.T) {
+	cases := []struct {
+		name   string
+		probs  map[string]float64
+		floor  float64
+		budget float64
+		used   float64
+		want   map[string]float64
+	}{
+		{
+			name:  "nobody below floor",
+			probs: map[string]float64{"a": 0.3, "b": 0.3, "c": 0.4},
+			floor: 0.1, budget: 0.5,
+			want: map[string]float64{"a": 0.3, "b": 0.3, "c": 0.4},
+		},
+		{
+			name:  "full lift taken from excess proportionally",
+			probs: map[string]float64{"a": 0.02, "b": 0.48, "c": 0.5},
+			floor: 0.1, budget: 0.5,
+			used: 0.08,
+			want: map[string]float64{"a": 0.1, "b": 0.48 - 0.38*0.08/0.78, "c": 0.5 - 0.4*0.08/0.78},
+		},
+		{
+			name:  "budget limits the lift",
+			probs: map[string]float64{"a": 0.02, "b": 0.06, "c": 0.92},
+			floor: 0.1, budget: 0.06,
+			used: 0.06,
+			want: map[string]float64{"a": 0.02 + 0.08*0.5, "b": 0.06 + 0.04*0.5, "c": 0.92 - 0.06},
+		},
+		{
+			name:  "zero budget means unlimited",
+			probs: map[string]float64{"a": 0.02, "b": 0.06, "c": 0.92},
+			floor: 0.1,
+			used:  0.12,
+			want:  map[string]float64{"a": 0.1, "b": 0.1, "c": 0.8},
+		},
+		{
+			name:  "lift bounded by available excess",
+			probs: map[string]float64{"a": 0.1, "b": 0.1, "c": 0.8},
+			floor: 0.5, budget: 1,
+			used: 0.3,
+			want: map[string]float64{"a": 0.25, "b": 0.25, "c": 0.5},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ps := entriesFromProbs(tc.probs)
+			used := applyProbFloor(ps, tc.floor, tc.budget)
+			if math.Abs(used-tc.used) > 1e-12 {
+				t.Fatalf("used = %v, want %v", used, tc.used)
+			}
+			checkProbs(t, ps, tc.want)
+
+			// 올린 양과 덜어낸 양이 같아야 총 확률이 보존된다
+			var up, down float64
+			for _, r := range ps {
+				if r.floorAdd > 0 {
+					up += r.floorAdd
+				} else {
+					down -= r.floorAdd
+				}
+			}
+			if math.Abs(up-used) > 1e-12 || math.Abs(down-used) > 1e-12 {
+				t.Fatalf("floorAdd up=%v down=%v, want both %v", up, down, used)
+			}
+		})
+	}
+}
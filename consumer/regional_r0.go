@@ -0,0 +1,66 @@
+// oracle/consumer/regional_r0.go
+package consumer
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// 권역별 R0 표 (KMA 갱신 주기마다 solar_radiation.go에서 교체)
+// 보상에 쓰는 값은 정책 R0 × (권역 R0 / 전국 R0): 같은 분위수 기준으로 구한 비율만 가져와
+// 거버넌스로 바뀐 정책 R0와 단위를 그대로 유지한다
+type regionalR0Table struct {
+	mu        sync.RWMutex
+	national  float64
+	regions   map[string]RegionR0
+	updatedAt time.Time
+}
+
+var regionalR0 = &regionalR0Table{regions: map[string]RegionR0{}}
+
+// 라운드에 기록하는 표 스냅샷 (reward_round.r0_regions)
+type R0RegionSnapshot struct {
+	National    float64    `json:"national"`
+	MinStations int        `json:"min_stations"`
+	Regions     []RegionR0 `json:"regions"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (t *regionalR0Table) Set(national float64, list []RegionR0, at time.Time) {
+	m := make(map[string]RegionR0, len(list))
+	for _, r := range list {
+		m[r.Region] = r
+	}
+	t.mu.Lock()
+	t.national, t.regions, t.updatedAt = national, m, at
+	t.mu.Unlock()
+}
+
+func (t *regionalR0Table) Snapshot(minStations int) R0RegionSnapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	s := R0RegionSnapshot{National: t.national, MinStations: minStations, UpdatedAt: t.updatedAt}
+	for _, r := range t.regions {
+		s.Regions = append(s.Regions, r)
+	}
+	sort.Slice(s.Regions, func(i, j int) bool { return s.Regions[i].Region < s.Regions[j].Region })
+	return s
+}
+
+// 권역 → 정책 R0에 곱할 배율. 표가 없거나 표본 지점이 부족한 권역은 (1, false) = 전국 R0
+func (s R0RegionSnapshot) factor(region string) (float64, bool) {
+	if s.National <= 0 {
+		return 1, false
+	}
+	for _, r := range s.Regions {
+		if r.Region != region {
+			continue
+		}
+		if r.Stations < s.MinStations || r.R0 <= 0 {
+			return 1, false
+		}
+		return r.R0 / s.National, true
+	}
+	return 1, false
+}
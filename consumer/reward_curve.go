@@ -0,0 +1,79 @@
+// oracle/consumer/reward_curve.go
+package consumer
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	CurveLinear    = "linear"    // f = r_eff (기존 γ=1)
+	CurvePower     = "power"     // f = r_eff^γ
+	CurveLogistic  = "logistic"  // 0..1로 정규화한 로지스틱 (K, Mid)
+	CurvePiecewise = "piecewise" // (r_eff, f) 점 사이 선형 보간
+)
+
+// bonusFraction: r_eff ∈ [0,1] → 보너스 비율 f ∈ [0,1] (bonus = Beta × f)
+// 알 수 없는 곡선/잘못된 인자는 linear로 처리
+func bonusFraction(p Policy, rEff float64) float64 {
+	x := clamp(rEff, 0, 1)
+	switch p.Curve {
+	case CurvePower:
+		if p.Gamma <= 0 {
+			return x
+		}
+		return math.Pow(x, p.Gamma)
+	case CurveLogistic:
+		if p.LogisticK <= 0 {
+			return x
+		}
+		s := func(v float64) float64 { return 1 / (1 + math.Exp(-p.LogisticK*(v-p.LogisticMid))) }
+		lo, hi := s(0), s(1)
+		if hi-lo <= 0 {
+			return x
+		}
+		return clamp((s(x)-lo)/(hi-lo), 0, 1)
+	case CurvePiecewise:
+		if len(p.Piecewise) == 0 {
+			return x
+		}
+		return piecewiseAt(p.Piecewise, x)
+	default:
+		return x
+	}
+}
+
+// 점 목록(x 정렬 무관) 보간: 첫 점 이전/마지막 점 이후는 끝값 유지
+func piecewiseAt(points [][2]float64, x float64) float64 {
+	pts := append([][2]float64(nil), points...)
+	sort.Slice(pts, func(i, j int) bool { return pts[i][0] < pts[j][0] })
+	if x <= pts[0][0] {
+		return clamp(pts[0][1], 0, 1)
+	}
+	for i := 1; i < len(pts); i++ {
+		if x <= pts[i][0] {
+			x0, y0, x1, y1 := pts[i-1][0], pts[i-1][1], pts[i][0], pts[i][1]
+			if x1 == x0 {
+				return clamp(y1, 0, 1)
+			}
+			return clamp(y0+(y1-y0)*(x-x0)/(x1-x0), 0, 1)
+		}
+	}
+	return clamp(pts[len(pts)-1][1], 0, 1)
+}
+
+// streakMultiplier: 연속 참여 streak번째 라운드의 주소별 배율
+// 1 + min(Cap, Step × (streak - StartAt + 1)), StartAt 이전이면 1
+func streakMultiplier(p Policy, streak int) float64 {
+	if p.StreakStep <= 0 || p.StreakCap <= 0 {
+		return 1
+	}
+	start := p.StreakStartAt
+	if start < 1 {
+		start = 1
+	}
+	if streak < start {
+		return 1
+	}
+	return 1 + math.Min(p.StreakCap, p.StreakStep*float64(streak-start+1))
+}
@@ -0,0 +1,357 @@
+// oracle/consumer/selection.go
+package consumer
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"oracle/config"
+	dbx "oracle/db"
+)
+
+// 선발 계산 결과 (DB 쓰기 없음). selectAndPublish와 what-if API가 공유
+type selectionResult struct {
+	Candidates []Contributor      // 기여자 ∪ vote-only (적격성 평가 전)
+	Excluded   map[string]string  // 주소 -> 제외 사유
+	EntityOf   map[string]string  // 주소 -> 엔티티
+	Addrs      []string           // 적격 후보
+	Scores     map[string]float64 // vote_counter 점수
+	Energy     map[string]float64 // 변환 후 에너지
+	X          map[string]float64 // 에너지 비중 x_i
+	R          map[string]float64 // 투표 비중 r_i
+	E, S       float64
+	Stats      map[string]winStat // 공정성 창 승리 통계 (FairOn일 때)
+	Table      []rouletteEntry    // 주소 정렬, P-cap 적용 후
+	Params     turnParamsRecord   // 사용한 파라미터 (+PcapTriggered, Seed, RandU)
+	Winner     string
+	WinnerW    float64
+}
+
+// computeSelection
+// - 스냅샷(snap)과 파라미터(p)로 후보 확률표를 만들고 시드 기반 룰렛으로 1명을 뽑는다
+// - DB는 읽기만 한다 (적격성 사실, 엔티티, 설비 용량, 승리 이력, 위치)
+// - 후보가 없으면 error
+func computeSelection(db *sql.DB, turn contributorTurn, snap dbx.VoteSnapshot, p turnParamsRecord) (*selectionResult, error) {
+	var err error
+	res := &selectionResult{Params: p, Scores: snap.Scores}
+
+	// snap의 맵은 인덱스 사본을 공유할 수 있으므로 읽기만 한다
+	unionCandidates := UnionContributorsWithVoteOnly(turn.Contributors, snap.VoteOnly)
+	res.Candidates = unionCandidates
+
+	// ★ 합집합 기준으로 검증(빈 기여자 선탈락 금지)
+	if len(unionCandidates) == 0 {
+		return nil, fmt.Errorf("no candidates (contributors ∪ vote-only empty)")
+	}
+
+	// 0) 적격성 규칙 평가 (가중치 계산 전) — 제외 사유는 turn_audit에 기록
+	contribSet := make(map[string]struct{}, len(turn.Contributors))
+	for _, c := range turn.Contributors {
+		if c.Address != "" {
+			contribSet[c.Address] = struct{}{}
+		}
+	}
+	rules := p.Eligibility
+	facts := map[string]dbx.AccountFacts{}
+	if rules.needsFacts() {
+		unionAddrs := make([]string, 0, len(unionCandidates))
+		for _, c := range unionCandidates {
+			unionAddrs = append(unionAddrs, c.Address)
+		}
+		ctxElig, cancelElig := context.WithTimeout(context.Background(), 3*time.Second)
+		facts, err = dbx.FetchAccountFacts(ctxElig, db, unionAddrs, rules.UseMissPenalty)
+		cancelElig()
+		if err != nil {
+			// 빈 사실로 평가하면 "전원 미등록"이 되어 턴이 조용히 비므로, 선발하지 않고 풀노드 재요청을 기다린다
+			return nil, fmt.Errorf("eligibility: account facts query failed (turn not decided, retry): %w", err)
+		}
+	}
+	eligibleContributors, excluded := applyEligibility(unionCandidates, contribSet, facts, rules, time.Now())
+	res.Excluded = excluded
+	if len(excluded) > 0 {
+		fmt.Printf("[Eligibility] excluded=%d eligible=%d\n", len(excluded), len(eligibleContributors))
+	}
+	if len(eligibleContributors) == 0 {
+		return nil, fmt.Errorf("no eligible candidates after rules")
+	}
+
+	// 1) 주소 목록 (합집합 기준)
+	addrs := make([]string, 0, len(eligibleContributors))
+	for _, c := range eligibleContributors {
+		if c.Address != "" {
+			addrs = append(addrs, c.Address)
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no valid addresses")
+	}
+
+	// 2) 누적 점수: 후보 집합과 같은 스냅샷 값
+	scoreMap := snap.Scores
+
+	// 2-1) 엔티티 그룹핑: 같은 운영자의 여러 주소를 하나로 보고 엔티티당 후보 1개로 합침
+	entityOf := identityEntities(addrs)
+	if p.EntityGrouping {
+		ctxEnt, cancelEnt := context.WithTimeout(context.Background(), 3*time.Second)
+		em, err := dbx.ResolveEntities(ctxEnt, db, addrs, config.EntityGroupKeys)
+		cancelEnt()
+		if err != nil {
+			fmt.Println("[Entity] resolve failed (fallback: address=entity):", err)
+		} else {
+			entityOf = em
+			before := len(eligibleContributors)
+			eligibleContributors = dedupeByEntity(eligibleContributors, contribSet, entityOf, scoreMap)
+			kept := make(map[string]struct{}, len(eligibleContributors))
+			for _, c := range eligibleContributors {
+				kept[c.Address] = struct{}{}
+			}
+			for _, a := range addrs {
+				if _, ok := kept[a]; !ok {
+					excluded[a] = ExclEntityDuplicate
+				}
+			}
+			addrs = addrs[:0]
+			for _, c := range eligibleContributors {
+				addrs = append(addrs, c.Address)
+			}
+			if dropped := before - len(eligibleContributors); dropped > 0 {
+				fmt.Printf("[Entity] duplicates merged=%d (candidates=%d)\n", dropped, len(addrs))
+			}
+		}
+	}
+	res.EntityOf = entityOf
+
+	// 3) x_i = e_i/E, r_i = count_i / sum(count)
+	var E float64
+	energy := make(map[string]float64, len(addrs)) // 단위 상관없음(합으로만 사용)
+	for _, c := range eligibleContributors {
+		if c.Address == "" {
+			continue
+		}
+		ekwh, _ := strconv.ParseFloat(c.EnergyKwh, 64) // 실패 시 0
+		if ekwh < 0 {
+			ekwh = 0
+		}
+		energy[c.Address] = ekwh
+		E += ekwh
+	}
+	// 3-1) 에너지 항 변환 (대형 발전소 독식 완화). linear면 기존과 동일.
+	if p.EnergyTransform != "" && p.EnergyTransform != EnergyLinear {
+		var caps map[string]float64
+		if p.EnergyTransform == EnergyCapacity {
+			ctxCap, cancelCap := context.WithTimeout(context.Background(), 2*time.Second)
+			caps, err = dbx.GetPlantCapacities(ctxCap, db, addrs)
+			cancelCap()
+			if err != nil {
+				fmt.Println("[Energy] plant_capacity query failed (default capacity used):", err)
+				caps = map[string]float64{}
+			}
+		}
+		energy = transformEnergy(energy, energyTransformOpts{
+			Mode:            p.EnergyTransform,
+			Capacities:      caps,
+			DefaultCapacity: config.EnergyDefaultCapacityKw,
+			ClipKwh:         config.EnergyClipKwh,
+			ClipQuantile:    config.EnergyClipQuantile,
+		})
+		E = 0
+		for _, a := range addrs {
+			E += energy[a]
+		}
+	}
+	var S float64
+	for _, a := range addrs {
+		S += scoreMap[a]
+	}
+	if E == 0 {
+		fmt.Println("[BlockCreator] note: E==0 (no energy this turn)")
+	}
+	if S == 0 {
+		fmt.Println("[BlockCreator] note: S==0 (no votes among union candidates)")
+	}
+	x := make(map[string]float64, len(addrs))
+	rv := make(map[string]float64, len(addrs))
+	for _, a := range addrs {
+		if E > 0 {
+			x[a] = energy[a] / E
+		} else {
+			x[a] = 0
+		}
+		if S > 0 {
+			rv[a] = scoreMap[a] / S
+		} else {
+			rv[a] = 0
+		}
+	}
+	res.Energy, res.X, res.R, res.E, res.S = energy, x, rv, E, S
+
+	// 4) w_i = β·x_i + (1-β)·r_i + ε  (룰렛휠)
+	beta, eps := p.Beta, p.Eps
+
+	ps := make([]rouletteEntry, 0, len(addrs))
+	var W float64
+	for _, a := range addrs {
+		w := beta*x[a] + (1.0-beta)*rv[a]
+		if w < 0 {
+			w = 0
+		}
+		// (선택) NaN/Inf 방어
+		if math.IsNaN(w) || math.IsInf(w, 0) {
+			w = 0
+		}
+		w += eps
+		ps = append(ps, rouletteEntry{addr: a, w: w, wBase: w, penalty: 1, geoFactor: 1})
+		W += w
+	}
+	if len(ps) == 0 {
+		return nil, fmt.Errorf("no candidates after weighting")
+	}
+
+	// [SAFE] 만약 모든 w가 0+eps 수준이라 W가 eps*len(addrs)에 매우 가깝더라도 정상 동작.
+	// 극히 드문 케이스로 W<=0이면 균등 분포로 대체.
+	if W <= 0 {
+		fmt.Println("[BlockCreator] note: W<=0 -> fallback to uniform weights")
+		ps = ps[:0]
+		for _, a := range addrs {
+			w := 1.0 // 균등
+			ps = append(ps, rouletteEntry{addr: a, w: w, wBase: w, penalty: 1, geoFactor: 1})
+		}
+		W = float64(len(addrs))
+	}
+
+	if p.FairOn {
+		// 턴 ID = 풀노드 height
+		currentTurn := turn.TurnID
+		ctxFair, cancelFair := context.WithTimeout(context.Background(), 2*time.Second)
+		// 엔티티 단위 집계: 같은 엔티티의 다른 주소가 이긴 턴도 합산
+		stats, err := fetchEntityWinStatsForWindow(ctxFair, db, currentTurn, p.FairWindowN, p.FairCapM, addrs, entityOf)
+		cancelFair()
+		if err != nil {
+			fmt.Println("[Fairness] fetchWinStats error:", err)
+		} else {
+			res.Stats = stats
+			// addr -> ps index 매핑 (ps에서 w를 바로 업데이트하기 위함)
+			idx := make(map[string]int, len(ps))
+			for i := range ps {
+				idx[ps[i].addr] = i
+			}
+
+			// ---- 패널티 적용 (ramp/fixed) ----
+			for a, s := range stats {
+				// 윈도우 내 승리 횟수가 M 초과 & (M+1)번째 최신 승리 턴 존재
+				if s.WinsInWindow > p.FairCapM && s.ExceedTurnID.Valid {
+					exceedTurn := s.ExceedTurnID.Int64
+					// R = 남은 패널티 턴수 = K - (currentTurn - exceedTurn)
+					R := p.FairSoftK - int(currentTurn-exceedTurn)
+					if R > 0 {
+						if i, ok := idx[a]; ok {
+							switch p.FairMode {
+							case "fixed":
+								ps[i].penalty = p.FairGamma
+							default: // "ramp"
+								ps[i].penalty = math.Pow(p.FairGamma, float64(R))
+							}
+							ps[i].w *= ps[i].penalty
+							ps[i].penaltyR = R
+						}
+					}
+				}
+			}
+
+			// ---- 패널티 적용 후 W 재계산 ----
+			W = 0
+			for i := range ps {
+				// 수치 안정성 보호
+				if ps[i].w < 0 || math.IsNaN(ps[i].w) || math.IsInf(ps[i].w, 0) {
+					ps[i].w = 0
+				}
+				W += ps[i].w
+			}
+			if W <= 0 {
+				// 만약 모든 가중치가 0이 되었다면 균등 분포로 복구
+				for i := range ps {
+					ps[i].w = 1.0
+				}
+				W = float64(len(ps))
+				fmt.Println("[Fairness] note: all-zero after penalty -> uniform fallback")
+			}
+		}
+	}
+	// 4-1) 권역 다양성 보정 (등록 위치 → bucketRegion 권역)
+	if p.GeoMode == GeoCap || p.GeoMode == GeoStratify {
+		ctxGeo, cancelGeo := context.WithTimeout(context.Background(), 3*time.Second)
+		locs, err := dbx.GetUserLocations(ctxGeo, db, addrs)
+		var shares map[string]float64
+		if err == nil && p.GeoMode == GeoCap {
+			var winners []string
+			winners, err = dbx.FetchRecentWinnerLocations(ctxGeo, db, turn.TurnID, p.GeoWindowN)
+			shares = regionWinShares(winners)
+		}
+		cancelGeo()
+		if err != nil {
+			fmt.Println("[Geo] region lookup failed (skip):", err)
+		} else {
+			W = applyGeoDiversity(ps, regionsOf(addrs, locs), p.GeoMode, shares,
+				p.GeoCapShare, p.GeoCapFactor)
+		}
+	}
+
+	// 5) P_i, F_i 계산 (주소 정렬로 재현성: map 반복 순서 제거)
+	sort.Slice(ps, func(i, j int) bool { return ps[i].addr < ps[j].addr })
+	acc := 0.0
+	for i := range ps {
+		if W > 0 {
+			ps[i].p = ps[i].w / W
+		} else {
+			ps[i].p = 0
+		}
+		acc += ps[i].p
+		ps[i].f = acc
+	}
+	ps[len(ps)-1].f = 1.0 // 수치오차 보호
+
+	// 5-1) 확률 하한 (P-cap 전): 신규/저점수 후보가 eps 수준에 머물지 않도록
+	if floor := probFloorLevel(p.FloorMode, p.FloorValue, len(ps)); floor > 0 {
+		res.Params.FloorUsed = applyProbFloor(ps, floor, p.FloorBudget)
+		if res.Params.FloorUsed > 0 {
+			fmt.Printf("[Floor] mode=%s floor=%.6f moved=%.6f (budget %.3f)\n", p.FloorMode, floor, res.Params.FloorUsed, p.FloorBudget)
+		}
+	}
+
+	for i := range ps {
+		ps[i].pPre = ps[i].p
+	}
+	if p.PcapOn {
+		// 엔티티 합산 확률 기준으로 캡 적용 + 잔여 확률 비례 재분배 (F_i 재계산 포함)
+		res.Params.PcapTriggered = applyEntityPcap(ps, entityOf, p.Pcap)
+	}
+
+	// 6) 재현 가능한 난수 시드: FullnodeID + height
+	sum := sha256.Sum256([]byte(turn.SeedMaterial))
+	seed := int64(binary.LittleEndian.Uint64(sum[:8]))
+	u := rand.New(rand.NewSource(seed)).Float64()
+	res.Params.Seed, res.Params.RandU = seed, u
+
+	// 7) 최초 F_i >= u 인 구간의 주소가 당첨
+	res.Winner = ps[len(ps)-1].addr
+	res.WinnerW = ps[len(ps)-1].w
+	for _, v := range ps {
+		if u <= v.f {
+			res.Winner = v.addr
+			res.WinnerW = v.w
+			break
+		}
+	}
+
+	res.Addrs = addrs
+	res.Table = ps
+	return res, nil
+}
@@ -0,0 +1,136 @@
+// oracle/consumer/selection_metrics.go
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"oracle/config"
+	dbx "oracle/db"
+	"oracle/metrics"
+)
+
+// 확률 분포 요약
+type probStats struct {
+	Entropy     float64 // -Σ p ln p
+	EntropyNorm float64 // Entropy / ln(n), n<=1이면 1
+	Gini        float64
+	HHI         float64 // Σ p²
+	Top1        float64
+}
+
+func probabilityStats(ps []rouletteEntry) probStats {
+	var s probStats
+	n := len(ps)
+	if n == 0 {
+		return s
+	}
+	p := make([]float64, 0, n)
+	for _, r := range ps {
+		p = append(p, r.p)
+		if r.p > 0 {
+			s.Entropy -= r.p * math.Log(r.p)
+		}
+		s.HHI += r.p * r.p
+		if r.p > s.Top1 {
+			s.Top1 = r.p
+		}
+	}
+	s.EntropyNorm = 1
+	if n > 1 {
+		s.EntropyNorm = s.Entropy / math.Log(float64(n))
+	}
+	// Gini = Σ (2i - n - 1) p_(i) / (n Σ p), p 오름차순
+	sort.Float64s(p)
+	var num, total float64
+	for i, v := range p {
+		num += float64(2*(i+1)-n-1) * v
+		total += v
+	}
+	if total > 0 {
+		s.Gini = num / (float64(n) * total)
+	}
+	return s
+}
+
+// 당첨자의 P 순위 구간 (라벨 카디널리티 고정)
+func winnerRankBucket(ps []rouletteEntry, winner string) string {
+	var wp float64
+	for _, r := range ps {
+		if r.addr == winner {
+			wp = r.p
+			break
+		}
+	}
+	rank := 1
+	for _, r := range ps {
+		if r.p > wp {
+			rank++
+		}
+	}
+	switch {
+	case rank <= 3:
+		return fmt.Sprint(rank)
+	case rank <= 5:
+		return "4-5"
+	case rank <= 10:
+		return "6-10"
+	default:
+		return "11+"
+	}
+}
+
+// 승리 점유율의 HHI와 최대 점유율
+func winConcentration(creators []string) (hhi, top float64) {
+	if len(creators) == 0 {
+		return 0, 0
+	}
+	cnt := map[string]int{}
+	for _, c := range creators {
+		cnt[c]++
+	}
+	n := float64(len(creators))
+	for _, k := range cnt {
+		sh := float64(k) / n
+		hhi += sh * sh
+		if sh > top {
+			top = sh
+		}
+	}
+	return hhi, top
+}
+
+// 턴 선발 후 분포 지표 갱신. 승리 집중도는 저장된 turn_result 기준 (finalize 이후 호출)
+func observeSelection(db *sql.DB, turnID int64, ps []rouletteEntry, winner string) {
+	s := probabilityStats(ps)
+	metrics.SelectionEntropyGauge.Set(s.Entropy)
+	metrics.SelectionEntropyNormGauge.Set(s.EntropyNorm)
+	metrics.SelectionGiniGauge.Set(s.Gini)
+	metrics.SelectionHHIGauge.Set(s.HHI)
+	metrics.SelectionTop1Gauge.Set(s.Top1)
+	metrics.SelectionGiniHistogram.Observe(s.Gini)
+	for _, r := range ps {
+		if r.addr == winner {
+			metrics.SelectionWinnerPHistogram.Observe(r.p)
+			break
+		}
+	}
+	metrics.WinnerRankCounter.WithLabelValues(winnerRankBucket(ps, winner)).Inc()
+	fmt.Printf("[Distribution] turn=%d H=%.4f Hn=%.4f gini=%.4f hhi=%.4f top1=%.4f\n",
+		turnID, s.Entropy, s.EntropyNorm, s.Gini, s.HHI, s.Top1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	creators, err := dbx.FetchRecentCreators(ctx, db, turnID, config.FairWinWindowN)
+	if err != nil {
+		fmt.Println("[Distribution] recent creators query failed:", err)
+		return
+	}
+	hhi, top := winConcentration(creators)
+	metrics.WinConcentrationHHIGauge.Set(hhi)
+	metrics.WinTopShareGauge.Set(top)
+}
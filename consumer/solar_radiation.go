@@ -119,6 +119,17 @@ func SaveSolarRadiationJSON(ctx context.Context) error {
 		}
 		fmt.Printf("[R0] %.6f %s (q*=%.6f, q10=%.6f, q90=%.6f, regions_used=%d, B=%.6f)\n",
 			r0, unit, qstar, q10, q90, used, conf.Bscale)
+
+		// 권역별 R0 표 (같은 분위수 기준, 보상에서는 전국 R0 대비 비율로 사용)
+		regions, err := ComputeRegionalR0FromJoined(conf.KMAJoinedOutPath, conf.EnableInverse, conf.Bscale, conf.Q_L, conf.Q_H)
+		if err != nil {
+			fmt.Printf("[R0-ERROR] regional compute failed; keep previous table: %v\n", err)
+		} else {
+			regionalR0.Set(r0, regions, time.Now().UTC())
+			for _, r := range regions {
+				fmt.Printf("[R0] region=%s r0=%.6f (m_r=%.6f, stations=%d)\n", r.Region, r.R0, r.Mr, r.Stations)
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,144 @@
+// oracle/consumer/turn_audit.go
+package consumer
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"oracle/config"
+	dbx "oracle/db"
+)
+
+// turn_result.params 에 저장하는 선발 파라미터 스냅샷 (사후 설명/재현용)
+type turnParamsRecord struct {
+	Beta            float64          `json:"beta"`
+	Eps             float64          `json:"eps"`
+	EnergyTransform string           `json:"energy_transform"`
+	EntityGrouping  bool             `json:"entity_grouping"`
+	FairOn          bool             `json:"fair_on"`
+	FairWindowN     int              `json:"fair_window_n"`
+	FairCapM        int              `json:"fair_cap_m"`
+	FairSoftK       int              `json:"fair_soft_k"`
+	FairGamma       float64          `json:"fair_gamma"`
+	FairMode        string           `json:"fair_mode"`
+	PcapOn          bool             `json:"pcap_on"`
+	Pcap            float64          `json:"pcap"`
+	PcapTriggered   bool             `json:"pcap_triggered"`
+	GeoMode         string           `json:"geo_mode"`
+	GeoWindowN      int              `json:"geo_window_n"`
+	GeoCapShare     float64          `json:"geo_cap_share"`
+	GeoCapFactor    float64          `json:"geo_cap_factor"`
+	FloorMode       string           `json:"floor_mode"`
+	FloorValue      float64          `json:"floor_value"`
+	FloorBudget     float64          `json:"floor_budget"`
+	FloorUsed       float64          `json:"floor_used"`
+	Eligibility     eligibilityRules `json:"eligibility"`
+	Governance      []string         `json:"governance,omitempty"` // 적용된 거버넌스 제안 ID (적용 순)
+	Seed            int64            `json:"seed"`
+	RandU           float64          `json:"rand_u"`
+}
+
+// 현재 설정 기준 선발 파라미터. eps/beta는 여기서 보정(eps>0, 0<=beta<=1)
+func currentTurnParams() turnParamsRecord {
+	eps := config.RouletteEps
+	if eps <= 0 {
+		eps = 1e-12
+	}
+	beta := config.BlockSelectBeta
+	if beta < 0 {
+		beta = 0
+	}
+	if beta > 1 {
+		beta = 1
+	}
+	return turnParamsRecord{
+		Beta:            beta,
+		Eps:             eps,
+		EnergyTransform: config.EnergyTransform,
+		EntityGrouping:  config.EntityGroupingOn,
+		FairOn:          config.FairFeatureOn,
+		FairWindowN:     config.FairWinWindowN,
+		FairCapM:        config.FairWinCapM,
+		FairSoftK:       config.FairSoftK,
+		FairGamma:       config.FairSoftGamma,
+		FairMode:        config.FairSoftMode,
+		PcapOn:          config.EnablePCap,
+		Pcap:            config.Pcap,
+		GeoMode:         config.GeoDiversityMode,
+		GeoWindowN:      config.GeoWinWindowN,
+		GeoCapShare:     config.GeoRegionCapShare,
+		GeoCapFactor:    config.GeoCapFactor,
+		FloorMode:       config.ProbFloorMode,
+		FloorValue:      config.ProbFloorValue,
+		FloorBudget:     config.ProbFloorBudget,
+		Eligibility:     currentEligibilityRules(),
+	}
+}
+
+func (p turnParamsRecord) marshal() []byte {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// 적격 후보(ps)와 제외 후보(excluded)를 감사 행으로 변환 (주소 정렬)
+func buildTurnAudit(
+	ps []rouletteEntry, excluded map[string]string, contribs []Contributor,
+	entityOf map[string]string, scoreMap, x, rv map[string]float64,
+) []dbx.TurnAuditRow {
+	rawEnergy := make(map[string]float64, len(contribs))
+	for _, c := range contribs {
+		e, _ := strconv.ParseFloat(c.EnergyKwh, 64)
+		if e < 0 {
+			e = 0
+		}
+		rawEnergy[c.Address] = e
+	}
+
+	out := make([]dbx.TurnAuditRow, 0, len(ps)+len(excluded))
+	for _, r := range ps {
+		ent := entityOf[r.addr]
+		if ent == "" {
+			ent = r.addr
+		}
+		out = append(out, dbx.TurnAuditRow{
+			Address:   r.addr,
+			EntityID:  ent,
+			Eligible:  true,
+			EnergyKwh: rawEnergy[r.addr],
+			VoteScore: scoreMap[r.addr],
+			X:         x[r.addr],
+			R:         rv[r.addr],
+			WBase:     r.wBase,
+			Penalty:   r.penalty,
+			PenaltyR:  r.penaltyR,
+			W:         r.w,
+			PPreCap:   r.pPre,
+			P:         r.p,
+			Region:    r.region,
+			GeoFactor: r.geoFactor,
+			PFloor:    r.floorAdd,
+		})
+	}
+	for a, reason := range excluded {
+		ent := entityOf[a]
+		if ent == "" {
+			ent = a
+		}
+		out = append(out, dbx.TurnAuditRow{
+			Address:   a,
+			EntityID:  ent,
+			Eligible:  false,
+			Reason:    reason,
+			EnergyKwh: rawEnergy[a],
+			VoteScore: scoreMap[a],
+			Penalty:   1,
+			GeoFactor: 1,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Address < out[j].Address })
+	return out
+}
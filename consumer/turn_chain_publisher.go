@@ -0,0 +1,63 @@
+// oracle/consumer/turn_chain_publisher.go
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"oracle/config"
+	dbx "oracle/db"
+)
+
+type turnChainHeadMsg struct {
+	Seq         int64  `json:"seq"`
+	TurnID      string `json:"turn_id"`
+	ContentHash string `json:"content_hash"`
+	PublishedAt string `json:"published_at"`
+}
+
+// StartTurnChainPublisher
+// - TurnChainPublishInterval마다 turn_result 체인 헤드를 TopicTurnChainHead로 공개
+// - 외부에서 공개된 헤드와 DB 체인을 대조해 사후 재작성을 감지할 수 있다
+func StartTurnChainPublisher(db *sql.DB, producer sarama.SyncProducer) {
+	every := config.TurnChainPublishInterval
+	if every <= 0 {
+		return
+	}
+	go func() {
+		t := time.NewTicker(every)
+		defer t.Stop()
+		for range t.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			head, ok, err := dbx.GetTurnChainHead(ctx, db)
+			cancel()
+			if err != nil {
+				fmt.Println("[TurnChain] head query failed:", err)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			b, _ := json.Marshal(turnChainHeadMsg{
+				Seq:         head.Seq,
+				TurnID:      head.TurnID,
+				ContentHash: head.ContentHash,
+				PublishedAt: time.Now().UTC().Format(time.RFC3339),
+			})
+			_, _, err = producer.SendMessage(&sarama.ProducerMessage{
+				Topic: config.TopicTurnChainHead,
+				Value: sarama.ByteEncoder(b),
+			})
+			if err != nil {
+				fmt.Println("[TurnChain] head publish failed:", err)
+				continue
+			}
+			fmt.Printf("[TurnChain] head published seq=%d turn=%s hash=%s\n", head.Seq, head.TurnID, head.ContentHash)
+		}
+	}()
+}
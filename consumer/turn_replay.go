@@ -0,0 +1,89 @@
+// oracle/consumer/turn_replay.go
+package consumer
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"oracle/config"
+	dbx "oracle/db"
+	"oracle/metrics"
+
+	"github.com/IBM/sarama"
+)
+
+// 기여자 목록의 정규화 해시 (주소 정렬, 에너지는 float로 파싱 후 재직렬화)
+func contributorsInputHash(contribs []Contributor) string {
+	lines := make([]string, 0, len(contribs))
+	for _, c := range contribs {
+		if c.Address == "" {
+			continue
+		}
+		e, _ := strconv.ParseFloat(c.EnergyKwh, 64)
+		lines = append(lines, c.Address+"="+strconv.FormatFloat(e, 'g', -1, 64))
+	}
+	sort.Strings(lines)
+	h := sha256.New()
+	for _, l := range lines {
+		h.Write([]byte(l))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// 이미 결정된 턴이면 저장된 결과를 그대로 재송신하고 true 반환 (재추첨 금지).
+// 입력 해시가 다르면 같은 턴에 다른 기여자 목록이 온 것이므로 충돌로 기록한다.
+func replayDecidedTurn(db *sql.DB, producer sarama.SyncProducer, turn contributorTurn, inputHash string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	prev, found, err := dbx.GetTurnResult(ctx, db, turn.TurnID)
+	cancel()
+	if err != nil {
+		// 조회 실패 시엔 선발을 진행 (finalize가 turn_id를 선점하므로 이미 결정된 턴이면 저장 결과를 송신)
+		fmt.Printf("[Replay] turn_result lookup failed: %v (turn_id=%d)\n", err, turn.TurnID)
+		return false
+	}
+	if !found {
+		return false
+	}
+
+	metrics.TurnReplayCounter.Inc()
+	if prev.InputHash != "" && prev.InputHash != inputHash {
+		metrics.TurnConflictCounter.Inc()
+		fmt.Printf("[Replay] CONFLICT turn=%d: stored(fullnode=%s, input=%.12s) != request(fullnode=%s, input=%.12s) — stored result kept\n",
+			turn.TurnID, prev.FullnodeID, prev.InputHash, turn.FullnodeID, inputHash)
+	}
+
+	msg := BlockCreatorMsg{
+		Creator:      prev.Creator,
+		Contribution: prev.Weight,
+		FullnodeID:   prev.FullnodeID,
+		TurnID:       prev.TurnID,
+		Replayed:     true,
+	}
+	if err := publishBlockCreator(producer, msg); err != nil {
+		fmt.Printf("[Replay] send failed: %v (turn_id=%d)\n", err, turn.TurnID)
+		return true
+	}
+	fmt.Printf("[Replay] turn=%d already decided → re-sent creator=%s (requested by fullnode=%s)\n",
+		turn.TurnID, prev.Creator, turn.FullnodeID)
+	return true
+}
+
+func publishBlockCreator(producer sarama.SyncProducer, msg BlockCreatorMsg) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	_, _, err = producer.SendMessage(&sarama.ProducerMessage{
+		Topic: config.TopicBlockCreator,
+		Value: sarama.ByteEncoder(payload),
+	})
+	return err
+}
@@ -9,13 +9,14 @@ import (
 	"time"
 
 	"github.com/IBM/sarama"
+	"github.com/segmentio/kafka-go"
 
 	"oracle/config"
 	dbx "oracle/db"
 	"oracle/types"
 )
 
-func StartVMemberRewardConsumer(db *sql.DB /* writer 제거됨 */) error {
+func StartVMemberRewardConsumer(db *sql.DB, writer *kafka.Writer) error {
 	{
 		ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
 		err := SaveSolarRadiationJSON(ctx)
@@ -43,6 +44,8 @@ func StartVMemberRewardConsumer(db *sql.DB /* writer 제거됨 */) error {
 		return err
 	}
 
+	go retryUnpublishedRewardRounds(db, writer)
+
 	go func() {
 		defer func() { _ = pc.Close(); _ = cons.Close() }()
 
@@ -57,31 +60,96 @@ func StartVMemberRewardConsumer(db *sql.DB /* writer 제거됨 */) error {
 				continue
 			}
 
-			// 보상 계산 (올바른 인자 사용)
+			// 보상 계산 + 원장 기록 + vote_counter 누적 (한 트랜잭션)
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			rewardsMap, err := ComputeRewards(ctx, db, req.Validators, DefaultPolicy())
+			round, credits, err := ComputeRewards(ctx, db, req.FullnodeID, req.Timestamp, req.Validators, req.Expected, governedRewardPolicy(db))
 			cancel()
 			if err != nil {
 				log.Printf("[VMember] 보상 계산 실패: %v", err)
 				continue
 			}
 
-			// 송금/응답 전송 대신 vote_counter에 점수 누적
-			upserted := 0
-			for addr, score := range rewardsMap {
-				if score <= 0 {
-					continue
-				}
-				if err := dbx.UpsertVoteCounter(context.Background(), db, addr, score); err != nil {
-					log.Printf("[VMember] vote_counter upsert 실패 addr=%s score=%.8f err=%v", addr, score, err)
-					continue
-				}
-				upserted++
+			// NOTIFY 도착 전에도 다음 턴 선발에 반영
+			for _, c := range credits {
+				voteIndex.Set(c.Address, c.AfterCount)
 			}
 
-			log.Printf("[VMember] 보상 누적 완료: 대상=%d, fullnode_id=%s, ts=%s",
-				upserted, req.FullnodeID, time.Now().UTC().Format(time.RFC3339))
+			log.Printf("[VMember] 보상 누적 완료: round=%d 대상=%d 미참여=%d, fullnode_id=%s, ts=%s",
+				round.RoundID, len(credits)-round.Missed, round.Missed, req.FullnodeID, time.Now().UTC().Format(time.RFC3339))
+
+			// 커밋된 적립 내역을 요청한 풀노드로 송신
+			publishRewardRound(db, writer, round, credits)
 		}
 	}()
 	return nil
 }
+
+// 라운드 적립 결과 송신 (key = 풀노드 ID). 성공 시 reward_round.published_at 기록
+// 실패한 라운드는 retryUnpublishedRewardRounds가 다시 보낸다 (풀노드는 round_id로 중복 수신을 거른다)
+func publishRewardRound(db *sql.DB, writer *kafka.Writer, round dbx.RewardRound, credits []dbx.RewardCredit) {
+	if writer == nil {
+		return
+	}
+	out := types.MemberRewardOutputMessage{
+		SenderID:      round.FullnodeID,
+		Rewards:       make(map[string]float64, len(credits)),
+		RoundID:       round.RoundID,
+		PolicyVersion: round.PolicyVersion,
+		BaseReward:    round.BaseReward,
+		Timestamp:     round.RequestTS,
+	}
+	// 미참여 차감은 음수로 포함 (풀노드 쪽 잔고와 vote_counter를 맞추기 위함)
+	for _, c := range credits {
+		out.Rewards[c.Address] = c.Reward
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		log.Printf("[VMember] 보상 결과 직렬화 실패 round=%d: %v", round.RoundID, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(round.FullnodeID), // 같은 풀노드는 같은 파티션
+		Value: b,
+	}); err != nil {
+		log.Printf("[VMember] 보상 결과 전송 실패 round=%d fullnode_id=%s: %v", round.RoundID, round.FullnodeID, err)
+		return
+	}
+	if err := dbx.MarkRewardRoundPublished(ctx, db, round.RoundID); err != nil {
+		log.Printf("[VMember] published_at 기록 실패 round=%d: %v", round.RoundID, err)
+	}
+}
+
+// retryUnpublishedRewardRounds
+// - RewardPublishRetryInterval마다 published_at이 빈 라운드를 원장에서 다시 읽어 송신
+// - 송신 실패, 송신 전 프로세스 종료, published_at 기록 실패 모두 여기서 회복된다
+func retryUnpublishedRewardRounds(db *sql.DB, writer *kafka.Writer) {
+	every := config.RewardPublishRetryInterval
+	if writer == nil || every <= 0 {
+		return
+	}
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for range t.C {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		rounds, err := dbx.ListUnpublishedRewardRounds(ctx, db,
+			config.RewardPublishRetryMinAge, config.RewardPublishRetryMaxAge, 100)
+		cancel()
+		if err != nil {
+			log.Printf("[VMember] 미송신 라운드 조회 실패: %v", err)
+			continue
+		}
+		for _, round := range rounds {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			credits, err := dbx.ListRewardRoundCredits(ctx, db, round.RoundID)
+			cancel()
+			if err != nil {
+				log.Printf("[VMember] 재송신 적립 조회 실패 round=%d: %v", round.RoundID, err)
+				continue
+			}
+			log.Printf("[VMember] 미송신 라운드 재송신 round=%d fullnode_id=%s", round.RoundID, round.FullnodeID)
+			publishRewardRound(db, writer, round, credits)
+		}
+	}
+}
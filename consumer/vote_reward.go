@@ -3,19 +3,55 @@ package consumer
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"log"
+	"math"
 	"oracle/config"
 	"time"
+
+	dbx "oracle/db"
 )
 
 // Policy: R0(기준보상), Beta(최대 보너스 비율), RStart(보너스 시작 참여율) 추가
 type Policy struct {
-	R0             float64 // 기준 보상 (이전 BaseReward 역할)
-	Beta           float64 // 최대 보너스 비율 (예: 0.5 => 최대 +50%)
-	RStart         float64 // 보너스 시작 참여율 [0,1] (예: 0.5)
-	UpperLimit     int     // (기존) 사용하지 않음: 과거 used 상한. 남겨두지만 보상엔 미반영.
-	InactivityDays int     // 미참여 초기화 기준 일수
+	R0             float64 `json:"r0"`              // 기준 보상 (이전 BaseReward 역할)
+	Beta           float64 `json:"beta"`            // 최대 보너스 비율 (예: 0.5 => 최대 +50%)
+	RStart         float64 `json:"r_start"`         // 보너스 시작 참여율 [0,1] (예: 0.5)
+	UpperLimit     int     `json:"upper_limit"`     // (기존) 사용하지 않음: 과거 used 상한. 남겨두지만 보상엔 미반영.
+	InactivityDays int     `json:"inactivity_days"` // 미참여 초기화 기준 일수
+
+	// 참여율 보너스 곡선 (reward_curve.go)
+	Curve       string       `json:"curve"`
+	Gamma       float64      `json:"gamma,omitempty"`
+	LogisticK   float64      `json:"logistic_k,omitempty"`
+	LogisticMid float64      `json:"logistic_mid,omitempty"`
+	Piecewise   [][2]float64 `json:"piecewise,omitempty"`
+
+	// 주소별 연속 참여 배율
+	StreakStep    float64 `json:"streak_step,omitempty"`
+	StreakCap     float64 `json:"streak_cap,omitempty"`
+	StreakStartAt int     `json:"streak_start_at,omitempty"`
+
+	// 기대 검증자 미참여 패널티
+	MissPenalty  float64 `json:"miss_penalty,omitempty"`   // 미참여 1회당 차감
+	MissBanAfter int     `json:"miss_ban_after,omitempty"` // 연속 미참여 N회부터 선발 일시 제외 (0 = 미적용)
+	MissBanHours int     `json:"miss_ban_hours,omitempty"`
+
+	// 권역별 R0 (regional_r0.go): 주소별 R0 = R0 × (등록 권역 R0 / 전국 R0)
+	R0Regional    bool `json:"r0_regional,omitempty"`
+	R0MinStations int  `json:"r0_min_stations,omitempty"` // 관측지점이 이보다 적은 권역은 전국 R0
+
+	Governance []string `json:"governance,omitempty"` // 적용된 거버넌스 제안 ID (활성화 순)
+}
+
+// 정책 버전: 정책 JSON(거버넌스 제안 ID 포함)의 sha256 앞 16자리
+func rewardPolicyVersion(policyJSON []byte) string {
+	sum := sha256.Sum256(policyJSON)
+	return hex.EncodeToString(sum[:])[:16]
 }
 
 func DefaultPolicy() Policy {
@@ -26,91 +62,242 @@ func DefaultPolicy() Policy {
 		RStart:         0.5,
 		InactivityDays: 7,
 		UpperLimit:     30,
+		Curve:          config.RewardBonusCurve,
+		Gamma:          config.RewardBonusGamma,
+		LogisticK:      config.RewardLogisticK,
+		LogisticMid:    config.RewardLogisticMid,
+		Piecewise:      config.RewardPiecewise,
+		StreakStep:     config.RewardStreakStep,
+		StreakCap:      config.RewardStreakCap,
+		StreakStartAt:  config.RewardStreakStartAt,
+		MissPenalty:    config.PenaltyMissDeduction,
+		MissBanAfter:   config.PenaltyBanAfterMisses,
+		MissBanHours:   config.PenaltyBanHours,
+		R0Regional:     config.RewardR0Regional,
+		R0MinStations:  config.RewardR0MinStations,
 	}
 }
 
-// 필요시 실제 스키마에 맞게 수정하세요.
-// const userTable = "users" // 만약 테이블이 "user"라면 쌍따옴표로 감싸서 사용: FROM \"user\"
-
-// ComputeRewards: 라운드 단위로 n, N을 먼저 구해 BaseReward를 공통 산출
-func ComputeRewards(ctx context.Context, db *sql.DB, addrs []string, policy Policy) (map[string]float64, error) {
+// ComputeRewards
+// - 라운드 단위로 n, N을 먼저 구해 BaseReward를 공통 산출
+// - reward_round + 주소별 reward_credit + vote_counter 갱신을 한 트랜잭션으로 기록
+// - EmissionOn이면 epoch 남은 예산을 넘는 라운드는 비례 축소 (emission.go)
+// - expected(기대 검증자 집합)가 있으면 미참여자도 같은 라운드에 kind=miss로 기록 (차감/선발 제외)
+func ComputeRewards(ctx context.Context, db *sql.DB, fullnodeID, requestTS string, addrs, expected []string, policy Policy) (dbx.RewardRound, []dbx.RewardCredit, error) {
 	now := time.Now().UTC()
 
 	uniq := unique(addrs)
 	n := len(uniq)
+	exp := unique(expected)
+	missed := missedValidators(exp, uniq)
 
-	// N: 전체 유저 수
-	var N int = config.LightNodeUser
-	// 주: 실제 테이블명이 "user"면 다음 쿼리를 `SELECT COUNT(*) FROM "user"`로 바꾸세요.
-	//if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+userTable).Scan(&N); err != nil {
-	//	return nil, err
-	//}
-	// 참여율 기반 BaseReward 계산 (γ=1 고정 → 선형)
+	// N: 요청 시점 적격 검증자 수 (정의는 config.RewardNSource)
+	N, nSource, err := rewardDenominator(ctx, db, fullnodeID, uniq, now)
+	if err != nil {
+		return dbx.RewardRound{}, nil, err
+	}
+	// 참여율 기반 BaseReward 계산 (보너스 곡선은 policy.Curve)
 	base := computeBaseRewardFromParticipation(n, N, policy)
 
-	log.Printf("[Reward] 참여율: %.2f (n=%d, N=%d), 산출 BaseReward=%.4f, Policy={R0=%.2f, Beta=%.2f, RStart=%.2f}",
-		float64(n)/float64(N), n, N, base, policy.R0, policy.Beta, policy.RStart)
+	rate := 0.0
+	if N > 0 {
+		rate = float64(n) / float64(N)
+	}
+	log.Printf("[Reward] 참여율: %.2f (n=%d, N=%d %s), 산출 BaseReward=%.4f, Policy={R0=%.2f, Beta=%.2f, RStart=%.2f, Curve=%s}",
+		rate, n, N, nSource, base, policy.R0, policy.Beta, policy.RStart, policy.Curve)
 
-	out := make(map[string]float64, len(uniq))
-	for _, addr := range uniq {
-		r, err := computeOne(ctx, db, addr, now, policy, base)
-		if err != nil {
-			return nil, err
+	policyJSON, err := json.Marshal(policy)
+	if err != nil {
+		return dbx.RewardRound{}, nil, err
+	}
+	round := dbx.RewardRound{
+		FullnodeID:    fullnodeID,
+		RequestTS:     requestTS,
+		Participants:  n,
+		Total:         N,
+		TotalSource:   nSource,
+		Rate:          rate,
+		R0:            policy.R0,
+		Policy:        policyJSON,
+		PolicyVersion: rewardPolicyVersion(policyJSON),
+		BaseReward:    base,
+		Expected:      len(exp),
+	}
+	// 주소별 R0 (권역별 R0 미적용이면 모두 정책 R0)
+	r0Of, err := rewardR0s(ctx, db, uniq, missed, policy, &round)
+	if err != nil {
+		return round, nil, err
+	}
+	round = withEmission(round, now)
+	round, credits, err := dbx.RecordRewardRoundTx(ctx, db, round, uniq, missed,
+		func(st dbx.VoteCounterState) dbx.RewardCredit {
+			a := r0Of(st.Address)
+			if !policy.R0Regional {
+				return rewardCredit(st, now, policy, base, a)
+			}
+			pa := policy
+			pa.R0 = a.R0
+			return rewardCredit(st, now, policy, computeBaseRewardFromParticipation(n, N, pa), a)
+		},
+		func(st dbx.VoteCounterState) dbx.RewardCredit {
+			c := missCredit(st, now, policy)
+			c.Region = r0Of(st.Address).Region
+			return c
+		})
+	if err != nil {
+		return round, nil, err
+	}
+	observeEmission(round)
+	if round.EmissionScale < 1 {
+		log.Printf("[Reward] round=%d epoch 예산 초과: 보상 x%.4f 비례 축소 (budget=%.4f, spent=%.4f)",
+			round.RoundID, round.EmissionScale, round.EmissionBudget, round.EmissionSpent)
+	}
+	for _, c := range credits {
+		if c.Kind == dbx.CreditKindMiss {
+			log.Printf("[Penalty] round=%d Address=%s 미참여 %d회 연속, 차감=%.4f (count %.4f -> %.4f)%s",
+				round.RoundID, c.Address, c.MissStreak, -c.Reward, c.BeforeCount, c.AfterCount, banNote(c))
+			continue
 		}
-		out[addr] = r
-		log.Printf("[Reward] Address=%s 지급 BaseReward=%.4f", addr, r)
+		log.Printf("[Reward] round=%d Address=%s 지급 Reward=%.4f (R0=%.4f %s, streak=%d x%.3f, count %.4f -> %.4f)",
+			round.RoundID, c.Address, c.Reward, c.R0, c.Region, c.Streak, c.Multiplier, c.BeforeCount, c.AfterCount)
 	}
-	return out, nil
+	return round, credits, nil
 }
 
-// computeOne: 이전 로직 유지(카운터/last_date 관리)하되, 최종 보상은 BaseReward 그대로 반환
-func computeOne(ctx context.Context, db *sql.DB, addr string, now time.Time, p Policy, baseReward float64) (float64, error) {
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+// 기대 검증자 중 이번 라운드 미참여 주소 (expected 순서 유지)
+func missedValidators(expected, participants []string) []string {
+	if len(expected) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(participants))
+	for _, a := range participants {
+		seen[a] = struct{}{}
+	}
+	var out []string
+	for _, a := range expected {
+		if _, ok := seen[a]; !ok {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func banNote(c dbx.RewardCredit) string {
+	if c.IneligibleUntil == nil {
+		return ""
+	}
+	return ", 선발 제외 ~" + c.IneligibleUntil.Format(time.RFC3339)
+}
+
+// rewardDenominator: 참여율 분모 N과 그 산출 정의 문자열
+// - "config"  : config.LightNodeUser (StartUserMonitor 갱신값, 부팅 직후 0일 수 있음)
+// - "eligible": 등록 + 최근 RewardNActiveDays일 활동 (+ RewardNPerFullnode면 요청 풀노드 소속) + 이번 참여자
+func rewardDenominator(ctx context.Context, db *sql.DB, fullnodeID string, participants []string, now time.Time) (int, string, error) {
+	if config.RewardNSource == "config" {
+		return config.LightNodeUser, "config", nil
+	}
+	source := "eligible"
+	var since time.Time
+	if config.RewardNActiveDays > 0 {
+		since = now.Add(-time.Duration(config.RewardNActiveDays) * 24 * time.Hour)
+		source += fmt.Sprintf(":active=%dd", config.RewardNActiveDays)
+	}
+	nodeID := ""
+	if config.RewardNPerFullnode {
+		nodeID = fullnodeID
+		source += ":fullnode=" + fullnodeID
+	}
+	N, err := dbx.CountEligibleValidators(ctx, db, since, nodeID, participants)
 	if err != nil {
-		return 0, err
+		return 0, source, fmt.Errorf("eligible validator count: %w", err)
 	}
-	defer func() { _ = tx.Rollback() }()
+	return N, source, nil
+}
 
-	var last sql.NullTime
-	var cnt int
+// 주소에 적용할 권역/R0
+type addrR0 struct {
+	Region string
+	R0     float64
+}
 
-	// 테이블/컬럼명은 실제 스키마에 맞게 조정
-	row := tx.QueryRowContext(ctx,
-		`SELECT last_date, count FROM vote_counter WHERE address=$1 FOR UPDATE`, addr)
-	scanErr := row.Scan(&last, &cnt)
-	if scanErr == sql.ErrNoRows {
-		if _, err := tx.ExecContext(ctx,
-			`INSERT INTO vote_counter(address,last_date,count) VALUES($1,NULL,0)`, addr); err != nil {
-			return 0, err
-		}
-		cnt = 0
-		last.Valid = false
-	} else if scanErr != nil {
-		return 0, scanErr
+// rewardR0s: 주소 → 적용 R0
+// - R0Regional이면 user_region 등록 위치의 권역 R0 (미등록/표본 부족 권역은 정책 R0), 표 스냅샷을 round에 기록
+// - 위치 조회 실패는 라운드 실패 (참여자마다 다른 기준이 섞이지 않게)
+func rewardR0s(ctx context.Context, db *sql.DB, participants, missed []string, p Policy, round *dbx.RewardRound) (func(addr string) addrR0, error) {
+	if !p.R0Regional {
+		return func(string) addrR0 { return addrR0{R0: p.R0} }, nil
 	}
+	snap := regionalR0.Snapshot(p.R0MinStations)
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return nil, err
+	}
+	round.R0Regions = b
 
-	// 미참여 초기화
-	if last.Valid && now.Sub(last.Time) >= time.Duration(p.InactivityDays)*24*time.Hour {
-		cnt = 0
+	all := append(append([]string(nil), participants...), missed...)
+	locs, err := dbx.GetUserLocations(ctx, db, all)
+	if err != nil {
+		return nil, fmt.Errorf("user region lookup: %w", err)
+	}
+	out := make(map[string]addrR0, len(all))
+	fallback := 0
+	for _, a := range all {
+		region := deriveRegion(locs[a], "")
+		f, ok := snap.factor(region)
+		if !ok {
+			fallback++
+		}
+		out[a] = addrR0{Region: region, R0: p.R0 * f}
+	}
+	if snap.National <= 0 {
+		log.Printf("[Reward] 권역별 R0 표 없음: 전원 전국 R0 적용")
+	} else if fallback > 0 {
+		log.Printf("[Reward] 권역별 R0: %d/%d 주소 전국 R0 대체 (미등록 또는 관측지점 < %d)", fallback, len(all), p.R0MinStations)
 	}
+	return func(a string) addrR0 { return out[a] }, nil
+}
 
-	// 이번 투표 참여 반영
-	cnt++
+// rewardCredit: 이전 로직 유지(카운터/last_time 관리), 보상 = BaseReward × 연속 참여 배율
+// count = (미참여 초기화 ? 0 : count) + 1 + reward
+func rewardCredit(st dbx.VoteCounterState, now time.Time, p Policy, baseReward float64, r0 addrR0) dbx.RewardCredit {
+	c := dbx.RewardCredit{Increment: 1, AfterLastTime: now, Streak: st.Streak + 1, Region: r0.Region, R0: r0.R0}
 
-	// DB 갱신
-	if _, err := tx.ExecContext(ctx,
-		`UPDATE vote_counter SET last_date=$2, count=$3 WHERE address=$1`, addr, now, cnt); err != nil {
-		return 0, err
+	cnt := st.Count
+	// 미참여 초기화 (연속 참여도 다시 1부터)
+	if st.LastTime.Valid && now.Sub(st.LastTime.Time) >= time.Duration(p.InactivityDays)*24*time.Hour {
+		cnt = 0
+		c.Reset = true
+		c.Streak = 1
 	}
-	if err := tx.Commit(); err != nil {
-		return 0, err
+	c.Multiplier = streakMultiplier(p, c.Streak)
+	c.Reward = baseReward * c.Multiplier
+	if c.Reward < 0 {
+		c.Reward = 0
 	}
+	c.AfterCount = cnt + c.Increment + c.Reward
+	return c
+}
 
-	// ❗총보상 임의식 제거: 참여율 기반 BaseReward 자체를 지급
-	return baseReward, nil
+// missCredit: 기대 검증자 미참여 1회
+// - count에서 MissPenalty 차감 (0 미만 불가), last_time은 유지, 연속 참여는 끊김
+// - 연속 미참여가 MissBanAfter 이상이면 MissBanHours 동안 선발 제외
+func missCredit(st dbx.VoteCounterState, now time.Time, p Policy) dbx.RewardCredit {
+	c := dbx.RewardCredit{Multiplier: 1, MissStreak: st.Missed + 1}
+	if st.LastTime.Valid {
+		c.AfterLastTime = st.LastTime.Time
+	}
+	if p.MissPenalty > 0 {
+		c.Reward = -math.Min(p.MissPenalty, math.Max(st.Count, 0))
+	}
+	c.AfterCount = st.Count + c.Reward
+	if p.MissBanAfter > 0 && c.MissStreak >= p.MissBanAfter && p.MissBanHours > 0 {
+		until := now.Add(time.Duration(p.MissBanHours) * time.Hour)
+		c.IneligibleUntil = &until
+	}
+	return c
 }
 
-// --- 참여율 기반 BaseReward 계산 (γ=1 고정) ---
+// --- 참여율 기반 BaseReward 계산 ---
 
 func clamp(x, lo, hi float64) float64 {
 	if x < lo {
@@ -123,7 +310,7 @@ func clamp(x, lo, hi float64) float64 {
 }
 
 // r_eff = clamp((r - r_start)/(1 - r_start), 0, 1)
-// bonus = Beta * r_eff^1 = Beta * r_eff
+// bonus = Beta * f(r_eff)  (f: bonusFraction, linear면 r_eff)
 // BaseReward = R0 * (1 + bonus)
 func computeBaseRewardFromParticipation(n, N int, p Policy) float64 {
 	if N <= 0 || n <= 0 || p.R0 <= 0 {
@@ -146,7 +333,7 @@ func computeBaseRewardFromParticipation(n, N int, p Policy) float64 {
 		rEff = clamp((r-p.RStart)/(1-p.RStart), 0, 1)
 	}
 
-	bonus := p.Beta * rEff // γ=1
+	bonus := p.Beta * bonusFraction(p, rEff)
 	return p.R0 * (1.0 + bonus)
 }
 
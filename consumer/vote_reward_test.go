@@ -0,0 +1,46 @@
+package consumer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"oracle/config"
+)
+
+// 라운드마다 N의 출처(config / eligible...)가 reward_round.n_source에 저장된다
+func TestComputeRewardsRecordsNSource(t *testing.T) {
+	db := openTestDB(t)
+	oldSource := config.RewardNSource
+	t.Cleanup(func() { config.RewardNSource = oldSource })
+
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"config", "config"},
+		{"eligible", "eligible"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.source, func(t *testing.T) {
+			config.RewardNSource = tc.source
+			ctx := context.Background()
+			round, _, err := ComputeRewards(ctx, db, "fn-nsource", "2026-01-01T00:00:00Z",
+				[]string{"nsource-a", "nsource-b"}, nil, DefaultPolicy())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(round.TotalSource, tc.want) {
+				t.Fatalf("returned n_source = %q, want prefix %q", round.TotalSource, tc.want)
+			}
+			var stored string
+			if err := db.QueryRowContext(ctx, `SELECT n_source FROM reward_round WHERE round_id = $1`,
+				round.RoundID).Scan(&stored); err != nil {
+				t.Fatal(err)
+			}
+			if stored != round.TotalSource {
+				t.Fatalf("stored n_source = %q, want %q", stored, round.TotalSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// oracle/consumer/what_if.go
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	dbx "oracle/db"
+)
+
+// WhatIfRequest : 운영자용 가상 선발 입력. 비어 있는 항목은 현재 설정값(+거버넌스 적용분) 사용
+type WhatIfRequest struct {
+	FullnodeID   string        `json:"fullnode_id"`
+	Contributors []Contributor `json:"contributors"`
+	TurnID       int64         `json:"turn_id,omitempty"` // 공정성 창 기준 턴 (0 = 마지막 저장 턴 + 1)
+	Seed         string        `json:"seed,omitempty"`    // 시드 재료 (비우면 fullnode_id:height:turn_id)
+
+	Beta        *float64 `json:"beta,omitempty"`
+	FairOn      *bool    `json:"fair_on,omitempty"`
+	FairWindowN *int     `json:"fair_window_n,omitempty"`
+	FairCapM    *int     `json:"fair_cap_m,omitempty"`
+	FairSoftK   *int     `json:"fair_soft_k,omitempty"`
+	FairGamma   *float64 `json:"fair_gamma,omitempty"`
+	FairMode    *string  `json:"fair_mode,omitempty"`
+	PcapOn      *bool    `json:"pcap_on,omitempty"`
+	Pcap        *float64 `json:"pcap,omitempty"`
+}
+
+// WhatIfResult : 확률표(제외 후보 포함, 주소 정렬)와 당첨자. DB에는 아무것도 쓰지 않는다
+type WhatIfResult struct {
+	TurnID     int64              `json:"turn_id"`
+	SnapshotID string             `json:"snapshot_id"`
+	Params     turnParamsRecord   `json:"params"`
+	Winner     string             `json:"winner"`
+	WinnerP    float64            `json:"winner_p"`
+	Table      []dbx.TurnAuditRow `json:"table"`
+}
+
+func (r WhatIfRequest) validate() error {
+	if r.Beta != nil && (*r.Beta < 0 || *r.Beta > 1) {
+		return fmt.Errorf("beta must be in [0,1]")
+	}
+	if r.Pcap != nil && (*r.Pcap <= 0 || *r.Pcap > 1) {
+		return fmt.Errorf("pcap must be in (0,1]")
+	}
+	if r.FairGamma != nil && (*r.FairGamma <= 0 || *r.FairGamma > 1) {
+		return fmt.Errorf("fair_gamma must be in (0,1]")
+	}
+	if r.FairMode != nil && *r.FairMode != "ramp" && *r.FairMode != "fixed" {
+		return fmt.Errorf("fair_mode must be ramp or fixed")
+	}
+	if (r.FairWindowN != nil && *r.FairWindowN < 1) ||
+		(r.FairCapM != nil && *r.FairCapM < 0) ||
+		(r.FairSoftK != nil && *r.FairSoftK < 0) {
+		return fmt.Errorf("fairness window/cap/k must be non-negative (window >= 1)")
+	}
+	return nil
+}
+
+func (r WhatIfRequest) apply(p turnParamsRecord) turnParamsRecord {
+	if r.Beta != nil {
+		p.Beta = *r.Beta
+	}
+	if r.FairOn != nil {
+		p.FairOn = *r.FairOn
+	}
+	if r.FairWindowN != nil {
+		p.FairWindowN = *r.FairWindowN
+	}
+	if r.FairCapM != nil {
+		p.FairCapM = *r.FairCapM
+	}
+	if r.FairSoftK != nil {
+		p.FairSoftK = *r.FairSoftK
+	}
+	if r.FairGamma != nil {
+		p.FairGamma = *r.FairGamma
+	}
+	if r.FairMode != nil {
+		p.FairMode = *r.FairMode
+	}
+	if r.PcapOn != nil {
+		p.PcapOn = *r.PcapOn
+	}
+	if r.Pcap != nil {
+		p.Pcap = *r.Pcap
+	}
+	return p
+}
+
+// WhatIfSelection
+// - 현재 DB 점수(인덱스/스냅샷)와 덮어쓴 파라미터로 선발 계산만 수행
+// - turn_result/turn_audit/Kafka 어디에도 기록하지 않는다
+func WhatIfSelection(db *sql.DB, req WhatIfRequest) (WhatIfResult, error) {
+	if err := req.validate(); err != nil {
+		return WhatIfResult{}, err
+	}
+	turnID := req.TurnID
+	if turnID <= 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		last, _, err := dbx.LatestTurnID(ctx, db)
+		cancel()
+		if err != nil {
+			return WhatIfResult{}, fmt.Errorf("latest turn lookup: %w", err)
+		}
+		turnID = last + 1
+	}
+	seed := req.Seed
+	if seed == "" {
+		seed = fmt.Sprintf("%s:height:%d", req.FullnodeID, turnID)
+	}
+	turn := contributorTurn{
+		FullnodeID:   req.FullnodeID,
+		Contributors: req.Contributors,
+		TurnID:       turnID,
+		SeedMaterial: seed,
+	}
+
+	snap := readSelectionVotes(db, turn.Contributors)
+	sel, err := computeSelection(db, turn, snap, req.apply(governance.TurnParams(currentTurnParams(), turnID)))
+	if err != nil {
+		return WhatIfResult{}, err
+	}
+	out := WhatIfResult{
+		TurnID:     turnID,
+		SnapshotID: snap.SnapshotID,
+		Params:     sel.Params,
+		Winner:     sel.Winner,
+		Table:      buildTurnAudit(sel.Table, sel.Excluded, sel.Candidates, sel.EntityOf, sel.Scores, sel.X, sel.R),
+	}
+	for _, row := range sel.Table {
+		if row.addr == sel.Winner {
+			out.WinnerP = row.p
+			break
+		}
+	}
+	return out, nil
+}
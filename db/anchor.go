@@ -0,0 +1,247 @@
+// oracle/db/anchor.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// 온체인 앵커링
+// - anchor_epoch: 한 번의 앵커링 (Merkle root + broadcast_tx_sync 결과)
+// - anchor_leaf : epoch에 포함된 잎 (kind+ref 당 1회만 앵커링)
+
+// 앵커링 대상 잎 1개 (Payload는 잎 해시 입력)
+type AnchorLeaf struct {
+	Kind     string `json:"kind"`
+	Ref      string `json:"ref"`
+	Payload  string `json:"-"`
+	LeafHash string `json:"leaf_hash"`
+}
+
+type AnchorEpoch struct {
+	EpochID     int64      `json:"epoch_id"`
+	MerkleRoot  string     `json:"merkle_root"`
+	LeafCount   int        `json:"leaf_count"`
+	Status      string     `json:"status"`
+	TxHash      string     `json:"tx_hash"`
+	RPCCode     int        `json:"rpc_code"`
+	RPCLog      string     `json:"rpc_log,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+}
+
+// 아직 앵커링되지 않은 체인 턴 (chain_seq 순, 잎 입력 = content_hash)
+func FetchUnanchoredTurnLeaves(ctx context.Context, db *sql.DB, limit int) ([]AnchorLeaf, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT t.turn_id, t.content_hash
+  FROM turn_result t
+ WHERE t.chain_seq IS NOT NULL
+   AND NOT EXISTS (SELECT 1 FROM anchor_leaf a WHERE a.kind = 'turn' AND a.ref = t.turn_id::text)
+ ORDER BY t.chain_seq
+ LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]AnchorLeaf, 0)
+	for rows.Next() {
+		l := AnchorLeaf{Kind: "turn"}
+		if err := rows.Scan(&l.Ref, &l.Payload); err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+// epoch + 잎을 한 트랜잭션으로 저장 (status=pending)
+func InsertAnchorEpoch(ctx context.Context, db *sql.DB, root string, leaves []AnchorLeaf) (epochID int64, err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+	if err = tx.QueryRowContext(ctx, `
+INSERT INTO anchor_epoch (merkle_root, leaf_count) VALUES ($1, $2) RETURNING epoch_id`,
+		root, len(leaves)).Scan(&epochID); err != nil {
+		return 0, err
+	}
+	kinds, refs, hashes := make([]string, len(leaves)), make([]string, len(leaves)), make([]string, len(leaves))
+	idx := make([]int64, len(leaves))
+	for i, l := range leaves {
+		kinds[i], refs[i], hashes[i], idx[i] = l.Kind, l.Ref, l.LeafHash, int64(i)
+	}
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO anchor_leaf (kind, ref, epoch_id, leaf_index, leaf_hash)
+SELECT u.kind, u.ref, $1, u.idx, u.hash
+  FROM unnest($2::text[], $3::text[], $4::int[], $5::text[]) AS u(kind, ref, idx, hash)`,
+		epochID, pq.Array(kinds), pq.Array(refs), pq.Array(idx), pq.Array(hashes))
+	return epochID, err
+}
+
+func MarkAnchorEpoch(ctx context.Context, db *sql.DB, epochID int64, status, txHash string, code int, log string) error {
+	_, err := db.ExecContext(ctx, `
+UPDATE anchor_epoch
+   SET status = $2, tx_hash = $3, rpc_code = $4, rpc_log = $5,
+       submitted_at = CASE WHEN $2 = 'submitted' THEN now() ELSE submitted_at END
+ WHERE epoch_id = $1`, epochID, status, txHash, code, log)
+	return err
+}
+
+// 제출되지 않은 epoch (재시도 대상)
+func ListUnsubmittedAnchorEpochs(ctx context.Context, db *sql.DB) ([]AnchorEpoch, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT epoch_id, merkle_root, leaf_count, status, tx_hash, rpc_code, rpc_log, created_at, submitted_at
+  FROM anchor_epoch
+ WHERE status <> 'submitted'
+ ORDER BY epoch_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]AnchorEpoch, 0)
+	for rows.Next() {
+		e, err := scanAnchorEpoch(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func GetAnchorEpoch(ctx context.Context, db *sql.DB, epochID int64) (AnchorEpoch, error) {
+	row := db.QueryRowContext(ctx, `
+SELECT epoch_id, merkle_root, leaf_count, status, tx_hash, rpc_code, rpc_log, created_at, submitted_at
+  FROM anchor_epoch WHERE epoch_id = $1`, epochID)
+	return scanAnchorEpoch(row)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAnchorEpoch(r rowScanner) (AnchorEpoch, error) {
+	var e AnchorEpoch
+	var sub sql.NullTime
+	if err := r.Scan(&e.EpochID, &e.MerkleRoot, &e.LeafCount, &e.Status, &e.TxHash, &e.RPCCode, &e.RPCLog,
+		&e.CreatedAt, &sub); err != nil {
+		return e, err
+	}
+	if sub.Valid {
+		t := sub.Time
+		e.SubmittedAt = &t
+	}
+	return e, nil
+}
+
+// 잎이 속한 epoch와 그 epoch의 잎 해시 전체 (leaf_index 순)
+func GetAnchorLeafEpoch(ctx context.Context, db *sql.DB, kind, ref string) (epochID int64, index int, hashes []string, found bool, err error) {
+	err = db.QueryRowContext(ctx, `
+SELECT epoch_id, leaf_index FROM anchor_leaf WHERE kind = $1 AND ref = $2`, kind, ref).Scan(&epochID, &index)
+	if err == sql.ErrNoRows {
+		return 0, 0, nil, false, nil
+	}
+	if err != nil {
+		return 0, 0, nil, false, err
+	}
+	rows, err := db.QueryContext(ctx, `
+SELECT leaf_hash FROM anchor_leaf WHERE epoch_id = $1 ORDER BY leaf_index`, epochID)
+	if err != nil {
+		return 0, 0, nil, false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var h string
+		if err := rows.Scan(&h); err != nil {
+			return 0, 0, nil, false, err
+		}
+		hashes = append(hashes, h)
+	}
+	return epochID, index, hashes, true, rows.Err()
+}
+
+// 잎 출처 행의 현재 값 (증명 검증 시 anchor_leaf에 저장된 해시만 믿지 않도록)
+//   - Payload: 지금 행에 있는 content_hash (잎 해시 입력)
+//   - Intact : 행 내용으로 content_hash를 다시 계산해 저장값과 일치하는지
+//     (보상은 현재 해시 형식(v3)으로 기록된 행만 일치한다)
+type AnchorLeafSource struct {
+	Payload string
+	Intact  bool
+}
+
+// kind/ref 잎의 출처 행(turn_result / reward_credit)을 다시 읽는다 (없으면 found=false)
+func GetAnchorLeafSource(ctx context.Context, db *sql.DB, kind, ref string) (src AnchorLeafSource, found bool, err error) {
+	switch kind {
+	case "turn":
+		var (
+			seq                                          sql.NullInt64
+			turnID, fid, creator, inHash, snapID, params string
+			prev                                         string
+			weight                                       float64
+		)
+		err = db.QueryRowContext(ctx, `
+SELECT chain_seq, turn_id, fullnode_id, creator, weight, input_hash, snapshot_id,
+       COALESCE(params::text, ''), content_hash, prev_hash
+  FROM turn_result WHERE turn_id = $1`, ref).Scan(&seq, &turnID, &fid, &creator, &weight, &inHash, &snapID,
+			&params, &src.Payload, &prev)
+		if err == sql.ErrNoRows {
+			return src, false, nil
+		}
+		if err != nil {
+			return src, false, err
+		}
+		src.Intact = seq.Valid &&
+			turnContentHash(seq.Int64, turnID, fid, creator, weight, inHash, snapID, []byte(params), prev) == src.Payload
+		return src, true, nil
+
+	case "reward":
+		roundStr, addr, ok := strings.Cut(ref, ":")
+		roundID, perr := strconv.ParseInt(roundStr, 10, 64)
+		if !ok || perr != nil {
+			return src, false, nil
+		}
+		c := RewardCredit{RoundID: roundID, Address: addr}
+		var beforeLast, afterLast, until sql.NullTime
+		var r0 sql.NullFloat64
+		err = db.QueryRowContext(ctx, `
+SELECT kind, before_count, before_last_time, reset, increment, streak, multiplier, reward,
+       after_count, after_last_time, miss_streak, ineligible_until, region, r0, content_hash
+  FROM reward_credit WHERE round_id = $1 AND address = $2`, roundID, addr).Scan(&c.Kind, &c.BeforeCount, &beforeLast,
+			&c.Reset, &c.Increment, &c.Streak, &c.Multiplier, &c.Reward, &c.AfterCount, &afterLast, &c.MissStreak,
+			&until, &c.Region, &r0, &c.ContentHash)
+		if err == sql.ErrNoRows {
+			return src, false, nil
+		}
+		if err != nil {
+			return src, false, err
+		}
+		if beforeLast.Valid {
+			t := beforeLast.Time
+			c.BeforeLastTime = &t
+		}
+		if afterLast.Valid {
+			c.AfterLastTime = afterLast.Time
+		}
+		if until.Valid {
+			t := until.Time
+			c.IneligibleUntil = &t
+		}
+		c.R0 = r0.Float64
+		src.Payload = c.ContentHash
+		src.Intact = rewardCreditHash(c) == c.ContentHash
+		return src, true, nil
+	}
+	return src, false, nil
+}
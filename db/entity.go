@@ -0,0 +1,336 @@
+// oracle/db/entity.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// 한 운영자가 여러 userData 행(디바이스 ID만 다르고 서명 키/소유자가 같은)을
+// 등록해 선발 확률을 부풀리는 것을 막기 위한 "엔티티" 그룹핑.
+// userData.node_id는 사용자를 등록한 풀노드이므로(같은 풀노드의 모든 사용자가 공유) 키로 쓰지 않는다.
+// - public_key   : 같은 서명 키
+// - device_owner : 디바이스 소유자 레지스트리(device_owner.owner_id)가 같은 디바이스
+// - entity_link  : 관리자가 수동으로 묶은 주소
+// 위 키 중 하나라도 공유하면 같은 엔티티로 본다(서로 다른 키를 거친 연결도 전이적으로 병합).
+
+// EntityGroup: 관리자 조회용 클러스터
+type EntityGroup struct {
+	EntityID  string   `json:"entity_id"`
+	Addresses []string `json:"addresses"`
+	Reasons   []string `json:"reasons"` // 예: "device_owner=abc", "public_key=...", "manual=op-1"
+}
+
+// 관리자 수동 연결: addresses를 entityID 하나로 묶는다(기존 연결은 덮어씀)
+func LinkEntityAddresses(ctx context.Context, db *sql.DB, entityID, note string, addresses []string) error {
+	if strings.TrimSpace(entityID) == "" {
+		return fmt.Errorf("LinkEntityAddresses: empty entity_id")
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+	_, err := db.ExecContext(ctx, `
+INSERT INTO entity_link (address, entity_id, note)
+SELECT a, $2, $3 FROM unnest($1::text[]) AS a
+ WHERE a <> ''
+ON CONFLICT (address) DO UPDATE
+SET entity_id = EXCLUDED.entity_id,
+    note      = EXCLUDED.note`,
+		pq.Array(addresses), entityID, note)
+	return err
+}
+
+// 후보 주소 → 엔티티 ID.
+// 반환 맵에는 후보뿐 아니라 같은 엔티티에 속한 다른 주소도 포함된다
+// (비후보 멤버의 과거 승리도 공정성 창에 합산하기 위함).
+// keys: 그룹핑 키 ("public_key", "device_owner")
+func ResolveEntities(ctx context.Context, db *sql.DB, addrs []string, keys []string) (map[string]string, error) {
+	out := make(map[string]string, len(addrs))
+	if len(addrs) == 0 {
+		return out, nil
+	}
+	g, err := loadEntityGraph(ctx, db, addrs, keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, grp := range g.groups() {
+		for _, a := range grp.Addresses {
+			out[a] = grp.EntityID
+		}
+	}
+	// userData/entity_link에 없는 주소는 자기 자신이 엔티티
+	for _, a := range addrs {
+		if _, ok := out[a]; !ok {
+			out[a] = a
+		}
+	}
+	return out, nil
+}
+
+// 관리자 조회: 주소가 2개 이상 묶인 클러스터 전체
+func ListEntityClusters(ctx context.Context, db *sql.DB, keys []string) ([]EntityGroup, error) {
+	g, err := loadEntityGraph(ctx, db, nil, keys)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]EntityGroup, 0)
+	for _, grp := range g.groups() {
+		if len(grp.Addresses) > 1 {
+			out = append(out, grp)
+		}
+	}
+	return out, nil
+}
+
+// ---------------- 내부: union-find ----------------
+
+type entityGraph struct {
+	parent  map[string]string
+	reasons map[string]map[string]struct{} // address -> 연결 사유
+	manual  map[string]string              // address -> 수동 entity_id
+}
+
+func newEntityGraph() *entityGraph {
+	return &entityGraph{
+		parent:  map[string]string{},
+		reasons: map[string]map[string]struct{}{},
+		manual:  map[string]string{},
+	}
+}
+
+func (g *entityGraph) find(a string) string {
+	if _, ok := g.parent[a]; !ok {
+		g.parent[a] = a
+	}
+	for g.parent[a] != a {
+		g.parent[a] = g.parent[g.parent[a]]
+		a = g.parent[a]
+	}
+	return a
+}
+
+func (g *entityGraph) union(a, b string) {
+	ra, rb := g.find(a), g.find(b)
+	if ra == rb {
+		return
+	}
+	// 작은 주소를 루트로 (결정적 entity_id)
+	if rb < ra {
+		ra, rb = rb, ra
+	}
+	g.parent[rb] = ra
+}
+
+func (g *entityGraph) addReason(a, r string) {
+	if g.reasons[a] == nil {
+		g.reasons[a] = map[string]struct{}{}
+	}
+	g.reasons[a][r] = struct{}{}
+}
+
+// 같은 키 값을 가진 주소끼리 병합
+func (g *entityGraph) linkBy(kind string, byKey map[string][]string) {
+	for k, members := range byKey {
+		if len(members) < 2 {
+			continue
+		}
+		for _, m := range members {
+			g.addReason(m, kind+"="+k)
+			g.union(members[0], m)
+		}
+	}
+}
+
+func (g *entityGraph) groups() []EntityGroup {
+	byRoot := map[string][]string{}
+	for a := range g.parent {
+		r := g.find(a)
+		byRoot[r] = append(byRoot[r], a)
+	}
+	out := make([]EntityGroup, 0, len(byRoot))
+	for root, members := range byRoot {
+		sort.Strings(members)
+		id := root
+		// 수동 연결이 있으면 그 ID를 우선 사용
+		for _, m := range members {
+			if mid, ok := g.manual[m]; ok {
+				id = "manual:" + mid
+				break
+			}
+		}
+		rs := map[string]struct{}{}
+		for _, m := range members {
+			for r := range g.reasons[m] {
+				rs[r] = struct{}{}
+			}
+		}
+		reasons := make([]string, 0, len(rs))
+		for r := range rs {
+			reasons = append(reasons, r)
+		}
+		sort.Strings(reasons)
+		out = append(out, EntityGroup{EntityID: id, Addresses: members, Reasons: reasons})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].EntityID < out[j].EntityID })
+	return out
+}
+
+// 그룹핑 키 → (FROM 절, 키 값 식). u = userData
+var entityKeySources = map[string][2]string{
+	"public_key":   {"userData u", "u.public_key"},
+	"device_owner": {"userData u JOIN device_owner o ON o.device_id = u.device_id", "o.owner_id"},
+}
+
+// 후보에서 출발해 키를 따라 확장하는 최대 단계 (A-B: public_key, B-C: device_owner, ... 연결 추적)
+const entityMaxHops = 16
+
+// addrs == nil 이면 전체 테이블, 아니면 후보에서 출발해 키를 공유하는 주소가 더 없을 때까지 확장
+func loadEntityGraph(ctx context.Context, db *sql.DB, addrs []string, keys []string) (*entityGraph, error) {
+	for _, key := range keys {
+		if _, ok := entityKeySources[key]; !ok {
+			return nil, fmt.Errorf("loadEntityGraph: unsupported key %q", key)
+		}
+	}
+	g := newEntityGraph()
+	for _, a := range addrs {
+		g.find(a)
+	}
+	// kind -> 키 값 -> 주소 집합 (단계마다 누적한 뒤 한 번에 병합)
+	byKind := map[string]map[string]map[string]struct{}{}
+	add := func(kind, k, a string) {
+		if byKind[kind] == nil {
+			byKind[kind] = map[string]map[string]struct{}{}
+		}
+		if byKind[kind][k] == nil {
+			byKind[kind][k] = map[string]struct{}{}
+		}
+		byKind[kind][k][a] = struct{}{}
+	}
+
+	seen := make(map[string]struct{}, len(addrs))
+	for _, a := range addrs {
+		seen[a] = struct{}{}
+	}
+	frontier := addrs
+	for hop := 0; ; hop++ {
+		var next []string
+		visit := func(kind, k, a string) {
+			add(kind, k, a)
+			g.find(a)
+			if _, ok := seen[a]; !ok {
+				seen[a] = struct{}{}
+				next = append(next, a)
+			}
+		}
+		for _, key := range keys {
+			src := entityKeySources[key]
+			query := fmt.Sprintf(`SELECT u.address, %[2]s FROM %[1]s WHERE u.address <> '' AND %[2]s <> ''`, src[0], src[1])
+			var args []any
+			if addrs != nil {
+				query += fmt.Sprintf(` AND %[2]s IN (SELECT %[2]s FROM %[1]s WHERE u.address = ANY($1))`, src[0], src[1])
+				args = append(args, pq.Array(frontier))
+			}
+			if err := scanEntityPairs(ctx, db, query, args, func(a, k string) { visit(key, k, a) }); err != nil {
+				return nil, err
+			}
+		}
+
+		// 수동 연결
+		query := `SELECT address, entity_id FROM entity_link`
+		var args []any
+		if addrs != nil {
+			query += ` WHERE entity_id IN (SELECT entity_id FROM entity_link WHERE address = ANY($1))`
+			args = append(args, pq.Array(frontier))
+		}
+		if err := scanEntityPairs(ctx, db, query, args, func(a, id string) {
+			g.manual[a] = id
+			visit("manual", id, a)
+		}); err != nil {
+			return nil, err
+		}
+
+		// 전체 테이블 로드는 한 번이면 닫혀 있음
+		if addrs == nil || len(next) == 0 {
+			break
+		}
+		if hop+1 >= entityMaxHops {
+			fmt.Printf("[Entity] expansion stopped at %d hops (%d addresses)\n", entityMaxHops, len(seen))
+			break
+		}
+		frontier = next
+	}
+
+	for _, kind := range append(append([]string(nil), keys...), "manual") {
+		byKey := make(map[string][]string, len(byKind[kind]))
+		for k, set := range byKind[kind] {
+			members := make([]string, 0, len(set))
+			for a := range set {
+				members = append(members, a)
+			}
+			sort.Strings(members)
+			byKey[k] = members
+		}
+		g.linkBy(kind, byKey)
+	}
+	return g, nil
+}
+
+func scanEntityPairs(ctx context.Context, db *sql.DB, query string, args []any, fn func(a, k string)) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a, k string
+		if err := rows.Scan(&a, &k); err != nil {
+			return err
+		}
+		fn(a, k)
+	}
+	return rows.Err()
+}
+
+// ---------------- 디바이스 소유자 레지스트리 ----------------
+
+type DeviceOwner struct {
+	DeviceID  string `json:"device_id"`
+	OwnerID   string `json:"owner_id"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// 디바이스 소유자 등록/갱신 (같은 owner_id의 디바이스로 등록된 주소는 같은 엔티티)
+func UpsertDeviceOwner(ctx context.Context, db *sql.DB, deviceID, ownerID string) error {
+	if strings.TrimSpace(deviceID) == "" || strings.TrimSpace(ownerID) == "" {
+		return fmt.Errorf("UpsertDeviceOwner: empty device_id or owner_id")
+	}
+	_, err := db.ExecContext(ctx, `
+INSERT INTO device_owner (device_id, owner_id, updated_at)
+VALUES ($1, $2, now())
+ON CONFLICT (device_id) DO UPDATE
+SET owner_id = EXCLUDED.owner_id, updated_at = now()`, deviceID, ownerID)
+	return err
+}
+
+func ListDeviceOwners(ctx context.Context, db *sql.DB) ([]DeviceOwner, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT device_id, owner_id, to_char(updated_at, 'YYYY-MM-DD"T"HH24:MI:SSOF') FROM device_owner ORDER BY owner_id, device_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []DeviceOwner{}
+	for rows.Next() {
+		var d DeviceOwner
+		if err := rows.Scan(&d.DeviceID, &d.OwnerID, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
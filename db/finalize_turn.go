@@ -3,258 +3,74 @@ package db
 import (
 	"context"
 	"database/sql"
-	"fmt"
-	"strings"
 )
 
-func BootstrapTurnTables(ctx context.Context, db *sql.DB) error {
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{}) // ReadCommitted 기본 OK
-	if err != nil {
-		return err
-	}
-	// 커밋 전에 에러나면 롤백, 커밋 성공했으면 no-op
-	defer func() { _ = tx.Rollback() }()
-
-	// 동시 실행 방지: 트랜잭션 락 (트랜잭션 종료와 함께 자동 해제)
-	if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('turn_schema_init'))`); err != nil {
-		return err
-	}
-
-	// 1) turn_result
-	if _, err = tx.ExecContext(ctx, `
-CREATE TABLE IF NOT EXISTS turn_result (
-  turn_id     TEXT PRIMARY KEY,
-  fullnode_id TEXT NOT NULL,
-  creator     TEXT NOT NULL,
-  weight      DOUBLE PRECISION NOT NULL,
-  created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
-);`); err != nil {
-		return err
-	}
-
-	// 2) vote_counter_ledger (vote_counter: address, last_time, count 반영)
-	if _, err = tx.ExecContext(ctx, `
-CREATE TABLE IF NOT EXISTS vote_counter_ledger (
-  turn_id           TEXT NOT NULL,
-  address           TEXT NOT NULL,
-  before_count      DOUBLE PRECISION NOT NULL,
-  after_count       DOUBLE PRECISION NOT NULL,
-  delta             DOUBLE PRECISION NOT NULL,
-  before_last_time  TIMESTAMPTZ,
-  after_last_time   TIMESTAMPTZ,
-  created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
-  PRIMARY KEY (turn_id, address)
-);
-CREATE INDEX IF NOT EXISTS idx_vcl_turn ON vote_counter_ledger (turn_id);
-CREATE INDEX IF NOT EXISTS idx_vcl_addr ON vote_counter_ledger (address);`); err != nil {
-		return err
-	}
-
-	// 여기서 단 한 번만 커밋
-	return tx.Commit()
-}
-
-// 앱 시작 시 1회 호출하여 테이블 보장
-func EnsureTurnTables(ctx context.Context, db *sql.DB) error {
-	const ddl = `
-CREATE TABLE IF NOT EXISTS turn_result (
-  turn_id     TEXT PRIMARY KEY,
-  fullnode_id TEXT NOT NULL,
-  creator     TEXT NOT NULL,
-  weight      DOUBLE PRECISION NOT NULL,
-  created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
-);
-CREATE TABLE IF NOT EXISTS vote_counter_ledger (
-  turn_id           TEXT NOT NULL,
-  address           TEXT NOT NULL,
-  before_count      DOUBLE PRECISION NOT NULL,
-  after_count       DOUBLE PRECISION NOT NULL,
-  delta             DOUBLE PRECISION NOT NULL,
-  before_last_time  TIMESTAMPTZ,
-  after_last_time   TIMESTAMPTZ,
-  created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
-  PRIMARY KEY (turn_id, address)
-);
-CREATE INDEX IF NOT EXISTS idx_vcl_turn ON vote_counter_ledger (turn_id);
-CREATE INDEX IF NOT EXISTS idx_vcl_addr ON vote_counter_ledger (address);`
-	_, err := db.ExecContext(ctx, ddl)
-	return err
+// 이미 결정된 턴 결과 (재요청 시 재추첨 없이 그대로 재송신)
+type TurnResult struct {
+	TurnID     int64
+	FullnodeID string
+	Creator    string
+	Weight     float64
+	InputHash  string
 }
 
-// VALUES ($1),($2),... 동적 생성
-func buildValuesPlaceholders(addrs []string, start int) (string, []any) {
-	var b strings.Builder
-	args := make([]any, 0, len(addrs))
-	for i, a := range addrs {
-		if i > 0 {
-			b.WriteString(",")
-		}
-		fmt.Fprintf(&b, "($%d)", start+i)
-		args = append(args, a)
-	}
-	return b.String(), args
-}
-
-// 이번 턴 "합집합 후보만" reset + ledger 기록 + idempotency
-// - turnID: 이번 턴 고유 식별자(consumer의 seedMaterial 사용 권장)
-// - addrs : 합집합 후보 주소 배열(중복 없는 상태로 넘겨주세요)
-func FinalizeTurnSubsetTx(
-	ctx context.Context, db *sql.DB,
-	turnID, fullnodeID, winner string, weight float64, addrs []string,
-) (err error) {
-
-	if len(addrs) == 0 {
-		return nil
+// turn_id에 해당하는 결과 조회. 없으면 found=false
+// legacy_offset 행(offset 키였을 수 있는 과거 턴)은 같은 height의 결과로 재송신하지 않도록 제외
+func GetTurnResult(ctx context.Context, db *sql.DB, turnID int64) (res TurnResult, found bool, err error) {
+	err = db.QueryRowContext(ctx, `
+SELECT turn_id, fullnode_id, creator, weight, input_hash
+  FROM turn_result
+ WHERE turn_id = $1
+   AND NOT legacy_offset`, turnID).Scan(&res.TurnID, &res.FullnodeID, &res.Creator, &res.Weight, &res.InputHash)
+	if err == sql.ErrNoRows {
+		return res, false, nil
 	}
-
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
 	if err != nil {
-		return err
+		return res, false, err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		} else {
-			err = tx.Commit()
-		}
-	}()
-
-	// 1) 동일 turn_id 병행 보호
-	if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, turnID); err != nil {
-		return err
-	}
-
-	// 2) idempotency: turn_result 선삽입(이미 있으면 스킵)
-	res, err := tx.ExecContext(ctx,
-		`INSERT INTO turn_result (turn_id, fullnode_id, creator, weight)
-		 VALUES ($1,$2,$3,$4)
-		 ON CONFLICT (turn_id) DO NOTHING`,
-		turnID, fullnodeID, winner, weight)
-	if err != nil {
-		return err
-	}
-	if aff, _ := res.RowsAffected(); aff == 0 {
-		return nil // 이미 처리된 턴
-	}
-
-	// 3) 후보만 reset + ledger 기록(last_time 포함)
-	vals, args := buildValuesPlaceholders(addrs, 1)
-	q := fmt.Sprintf(`
-WITH cand(address) AS (VALUES %s),
-uniq AS (
-  SELECT DISTINCT address FROM cand
-),
-sel AS (
-  SELECT v.address, v.count AS before_count, v.last_time AS before_last_time
-  FROM vote_counter v
-  JOIN uniq c ON c.address = v.address
-  WHERE v.count <> 0
-  FOR UPDATE
-),
-upd AS (
-  UPDATE vote_counter v
-     SET count = 0,
-         last_time = now()   -- 리셋 시각 반영(원치 않으면 이 줄 제거)
-    FROM sel s
-   WHERE v.address = s.address
-RETURNING v.address, s.before_count, s.before_last_time, v.last_time AS after_last_time
-)
-INSERT INTO vote_counter_ledger
-(turn_id, address, before_count, after_count, delta, before_last_time, after_last_time)
-SELECT $%d, u.address, u.before_count, 0, (0 - u.before_count), u.before_last_time, u.after_last_time
-  FROM upd u;
-`, vals, len(args)+1)
-
-	args = append(args, turnID)
-	if _, err = tx.ExecContext(ctx, q, args...); err != nil {
-		return err
-	}
-	return nil
+	return res, true, nil
 }
 
-// 전체 reset + ledger 기록
-func FinalizeTurnAllTx(
-	ctx context.Context, db *sql.DB,
-	turnID, fullnodeID, winner string, weight float64,
-) (err error) {
-
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		} else {
-			err = tx.Commit()
-		}
-	}()
+// legacy_offset 행의 개수와 가장 큰 turn_id (0건이면 max=0)
+func LegacyTurnRange(ctx context.Context, db *sql.DB) (count int, maxID int64, err error) {
+	err = db.QueryRowContext(ctx, `
+SELECT COUNT(*), COALESCE(MAX(turn_id), 0) FROM turn_result WHERE legacy_offset`).Scan(&count, &maxID)
+	return count, maxID, err
+}
 
-	if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, turnID); err != nil {
-		return err
+// 가장 최근에 저장된 턴 ID (what-if 기본 턴 계산용)
+func LatestTurnID(ctx context.Context, db *sql.DB) (int64, bool, error) {
+	var id int64
+	err := db.QueryRowContext(ctx, `
+SELECT turn_id FROM turn_result WHERE NOT legacy_offset ORDER BY created_at DESC LIMIT 1`).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
 	}
-
-	res, err := tx.ExecContext(ctx,
-		`INSERT INTO turn_result (turn_id, fullnode_id, creator, weight)
-		 VALUES ($1,$2,$3,$4)
-		 ON CONFLICT (turn_id) DO NOTHING`,
-		turnID, fullnodeID, winner, weight)
 	if err != nil {
-		return err
+		return 0, false, err
 	}
-	if aff, _ := res.RowsAffected(); aff == 0 {
-		return nil
-	}
-
-	const q = `
-WITH sel AS (
-  SELECT address, count AS before_count, last_time AS before_last_time
-    FROM vote_counter
-   WHERE count <> 0
-   FOR UPDATE
-),
-upd AS (
-  UPDATE vote_counter v
-     SET count = 0,
-         last_time = now()
-    FROM sel s
-   WHERE v.address = s.address
-RETURNING v.address, s.before_count, s.before_last_time, v.last_time AS after_last_time
-)
-INSERT INTO vote_counter_ledger
-(turn_id, address, before_count, after_count, delta, before_last_time, after_last_time)
-SELECT $1, u.address, u.before_count, 0, (0 - u.before_count), u.before_last_time, u.after_last_time
-  FROM upd u;`
-	_, err = tx.ExecContext(ctx, q, turnID)
-	return err
+	return id, true, nil
 }
 
-func FinalizeTurnNoResetTx(
-	ctx context.Context, db *sql.DB,
-	turnID int64, fullnodeID, winner string, weight float64,
-) (err error) {
-
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+// 최근 N턴(현재 턴 포함)의 당첨자 목록 (승리 집중도 지표용)
+func FetchRecentCreators(ctx context.Context, db *sql.DB, currentTurn int64, N int) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT creator
+  FROM turn_result
+ WHERE turn_id > $1 - $2
+   AND turn_id <= $1
+   AND NOT legacy_offset`, currentTurn, N)
 	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		} else {
-			err = tx.Commit()
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]string, 0, N)
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, err
 		}
-	}()
-
-	// 동일 turn_id 중복 방지 (멱등)
-	if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, turnID); err != nil {
-		return err
+		out = append(out, c)
 	}
-
-	_, err = tx.ExecContext(ctx, `
-        INSERT INTO turn_result (turn_id, fullnode_id, creator, weight)
-        VALUES ($1, $2, $3, $4)
-        ON CONFLICT (turn_id) DO NOTHING
-    `, turnID, fullnodeID, winner, weight)
-	return err
+	return out, rows.Err()
 }
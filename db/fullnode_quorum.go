@@ -0,0 +1,90 @@
+// oracle/db/fullnode_quorum.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// 등록된 풀노드 (기여자 보고 quorum 참여 자격)
+type Fullnode struct {
+	FullnodeID string `json:"fullnode_id"`
+	PublicKey  string `json:"public_key"`
+	Active     bool   `json:"active"`
+}
+
+// quorum 조정 시 풀노드 보고가 엇갈린 주소 1건
+type ContributorDisagreement struct {
+	Address      string   `json:"address"`
+	ReportedBy   []string `json:"reported_by"`
+	Reports      int      `json:"reports"`
+	TotalReports int      `json:"total_reports"`
+	MinKwh       float64  `json:"min_kwh"`
+	MaxKwh       float64  `json:"max_kwh"`
+	MedianKwh    float64  `json:"median_kwh"`
+	Dropped      bool     `json:"dropped"` // 과반 미달로 후보에서 제외
+}
+
+func IsActiveFullnode(ctx context.Context, db *sql.DB, fullnodeID string) (bool, error) {
+	var ok bool
+	err := db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM fullnode_registry WHERE fullnode_id = $1 AND active)`, fullnodeID).Scan(&ok)
+	return ok, err
+}
+
+func UpsertFullnode(ctx context.Context, db *sql.DB, f Fullnode) error {
+	if f.FullnodeID == "" {
+		return fmt.Errorf("UpsertFullnode: empty fullnode_id")
+	}
+	_, err := db.ExecContext(ctx, `
+INSERT INTO fullnode_registry (fullnode_id, public_key, active)
+VALUES ($1, $2, $3)
+ON CONFLICT (fullnode_id) DO UPDATE
+SET public_key = EXCLUDED.public_key,
+    active     = EXCLUDED.active`, f.FullnodeID, f.PublicKey, f.Active)
+	return err
+}
+
+func ListFullnodes(ctx context.Context, db *sql.DB) ([]Fullnode, error) {
+	rows, err := db.QueryContext(ctx, `SELECT fullnode_id, public_key, active FROM fullnode_registry ORDER BY fullnode_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]Fullnode, 0)
+	for rows.Next() {
+		var f Fullnode
+		if err := rows.Scan(&f.FullnodeID, &f.PublicKey, &f.Active); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+func InsertContributorDisagreements(ctx context.Context, db *sql.DB, turnID int64, ds []ContributorDisagreement) error {
+	if len(ds) == 0 {
+		return nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, d := range ds {
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO contributor_disagreement
+(turn_id, address, reported_by, reports, total_reports, min_kwh, max_kwh, median_kwh, dropped)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+ON CONFLICT (turn_id, address) DO NOTHING`,
+			turnID, d.Address, pq.Array(d.ReportedBy), d.Reports, d.TotalReports,
+			d.MinKwh, d.MaxKwh, d.MedianKwh, d.Dropped); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
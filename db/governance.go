@@ -0,0 +1,195 @@
+// oracle/db/governance.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// 풀노드 파라미터 거버넌스
+// - gov_proposal: 서명된 파라미터 변경 제안 (activate_turn부터 적용)
+// - gov_vote    : 풀노드별 찬반 (첫 투표만 유효)
+
+type GovProposal struct {
+	ProposalID   string     `json:"proposal_id"`
+	Proposer     string     `json:"proposer"`
+	Params       []byte     `json:"-"`
+	ActivateTurn int64      `json:"activate_turn"`
+	Nonce        string     `json:"nonce"`
+	Signature    string     `json:"signature"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+	Votes        []GovVote  `json:"votes"`
+}
+
+type GovVote struct {
+	ProposalID string    `json:"-"`
+	FullnodeID string    `json:"fullnode_id"`
+	Approve    bool      `json:"approve"`
+	Signature  string    `json:"signature"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func GetFullnode(ctx context.Context, db *sql.DB, fullnodeID string) (Fullnode, bool, error) {
+	var f Fullnode
+	err := db.QueryRowContext(ctx, `
+SELECT fullnode_id, public_key, active FROM fullnode_registry WHERE fullnode_id = $1`, fullnodeID).
+		Scan(&f.FullnodeID, &f.PublicKey, &f.Active)
+	if err == sql.ErrNoRows {
+		return f, false, nil
+	}
+	return f, err == nil, err
+}
+
+// 새 제안 저장 (같은 proposal_id면 무시, 저장 여부 반환)
+func InsertGovProposal(ctx context.Context, db *sql.DB, p GovProposal) (bool, error) {
+	res, err := db.ExecContext(ctx, `
+INSERT INTO gov_proposal (proposal_id, proposer, params, activate_turn, nonce, signature)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (proposal_id) DO NOTHING`,
+		p.ProposalID, p.Proposer, p.Params, p.ActivateTurn, p.Nonce, p.Signature)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func GetGovProposal(ctx context.Context, db *sql.DB, proposalID string) (GovProposal, bool, error) {
+	row := db.QueryRowContext(ctx, `
+SELECT proposal_id, proposer, params, activate_turn, nonce, signature, status, created_at, decided_at
+  FROM gov_proposal WHERE proposal_id = $1`, proposalID)
+	p, err := scanGovProposal(row)
+	if err == sql.ErrNoRows {
+		return p, false, nil
+	}
+	return p, err == nil, err
+}
+
+// 투표 저장 (풀노드당 첫 투표만, 저장 여부 반환)
+func InsertGovVote(ctx context.Context, db *sql.DB, v GovVote) (bool, error) {
+	res, err := db.ExecContext(ctx, `
+INSERT INTO gov_vote (proposal_id, fullnode_id, approve, signature)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (proposal_id, fullnode_id) DO NOTHING`,
+		v.ProposalID, v.FullnodeID, v.Approve, v.Signature)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// 현재 active 풀노드 기준 찬/반 집계와 active 풀노드 수
+func TallyGovVotes(ctx context.Context, db *sql.DB, proposalID string) (approve, reject, active int, err error) {
+	err = db.QueryRowContext(ctx, `
+SELECT COUNT(*) FILTER (WHERE v.approve),
+       COUNT(*) FILTER (WHERE NOT v.approve),
+       (SELECT COUNT(*) FROM fullnode_registry WHERE active)
+  FROM gov_vote v
+  JOIN fullnode_registry f ON f.fullnode_id = v.fullnode_id AND f.active
+ WHERE v.proposal_id = $1`, proposalID).Scan(&approve, &reject, &active)
+	return
+}
+
+// open 상태에서만 전이 (중복 결정 방지)
+func DecideGovProposal(ctx context.Context, db *sql.DB, proposalID, status string) (bool, error) {
+	res, err := db.ExecContext(ctx, `
+UPDATE gov_proposal SET status = $2, decided_at = now()
+ WHERE proposal_id = $1 AND status = 'open'`, proposalID, status)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// 승인된 제안 (적용 순서: activate_turn, 승인 시각)
+func ListApprovedGovProposals(ctx context.Context, db *sql.DB) ([]GovProposal, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT proposal_id, proposer, params, activate_turn, nonce, signature, status, created_at, decided_at
+  FROM gov_proposal
+ WHERE status = 'approved'
+ ORDER BY activate_turn, decided_at, proposal_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]GovProposal, 0)
+	for rows.Next() {
+		p, err := scanGovProposal(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// 제안 이력 + 투표 (최신순)
+func ListGovHistory(ctx context.Context, db *sql.DB, limit int) ([]GovProposal, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT proposal_id, proposer, params, activate_turn, nonce, signature, status, created_at, decided_at
+  FROM gov_proposal
+ ORDER BY created_at DESC
+ LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]GovProposal, 0)
+	idx := map[string]int{}
+	for rows.Next() {
+		p, err := scanGovProposal(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		p.Votes = []GovVote{}
+		idx[p.ProposalID] = len(out)
+		out = append(out, p)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil || len(out) == 0 {
+		return out, err
+	}
+
+	ids := make([]string, 0, len(out))
+	for _, p := range out {
+		ids = append(ids, p.ProposalID)
+	}
+	vrows, err := db.QueryContext(ctx, `
+SELECT proposal_id, fullnode_id, approve, signature, created_at
+  FROM gov_vote
+ WHERE proposal_id = ANY($1)
+ ORDER BY created_at`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer vrows.Close()
+	for vrows.Next() {
+		var v GovVote
+		if err := vrows.Scan(&v.ProposalID, &v.FullnodeID, &v.Approve, &v.Signature, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		i := idx[v.ProposalID]
+		out[i].Votes = append(out[i].Votes, v)
+	}
+	return out, vrows.Err()
+}
+
+func scanGovProposal(r rowScanner) (GovProposal, error) {
+	var p GovProposal
+	var decided sql.NullTime
+	err := r.Scan(&p.ProposalID, &p.Proposer, &p.Params, &p.ActivateTurn, &p.Nonce, &p.Signature,
+		&p.Status, &p.CreatedAt, &decided)
+	if decided.Valid {
+		t := decided.Time
+		p.DecidedAt = &t
+	}
+	return p, err
+}
@@ -0,0 +1,312 @@
+// oracle/db/migrate.go
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// 스키마 마이그레이션
+// - migrations/NNN_name.up.sql / NNN_name.down.sql 을 바이너리에 포함
+// - 적용 이력은 schema_migrations (version, name, checksum)
+// - up/down 은 세션 advisory lock 하에서 한 버전씩 별도 트랜잭션으로 실행
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // sha256(up)
+}
+
+// 마이그레이션별 적용 상태 (migrate status)
+type MigrationState struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Modified  bool       `json:"modified"` // 적용 후 up 파일이 바뀜
+	Unknown   bool       `json:"unknown"`  // DB에는 있으나 바이너리에 없는 버전
+}
+
+const schemaMigrationsDDL = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version    INT PRIMARY KEY,
+  name       TEXT NOT NULL,
+  checksum   TEXT NOT NULL,
+  applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);`
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// LoadMigrations : 포함된 마이그레이션을 버전 순으로 반환 (up 파일 필수)
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	byVer := map[int]*Migration{}
+	for _, e := range entries {
+		m := migrationFileRe.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("migration %s: name must be NNN_name.up.sql or NNN_name.down.sql", e.Name())
+		}
+		v, _ := strconv.Atoi(m[1])
+		body, err := migrationFiles.ReadFile("migrations/" + e.Name())
+		if err != nil {
+			return nil, err
+		}
+		mg := byVer[v]
+		if mg == nil {
+			mg = &Migration{Version: v, Name: m[2]}
+			byVer[v] = mg
+		} else if mg.Name != m[2] {
+			return nil, fmt.Errorf("migration %d: conflicting names %q and %q", v, mg.Name, m[2])
+		}
+		if m[3] == "up" {
+			mg.Up = string(body)
+			sum := sha256.Sum256(body)
+			mg.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mg.Down = string(body)
+		}
+	}
+	out := make([]Migration, 0, len(byVer))
+	for _, mg := range byVer {
+		if mg.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s: missing up file", mg.Version, mg.Name)
+		}
+		out = append(out, *mg)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// LatestMigrationVersion : 이 바이너리가 기대하는 스키마 버전
+func LatestMigrationVersion() (int, error) {
+	ms, err := LoadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if len(ms) == 0 {
+		return 0, nil
+	}
+	return ms[len(ms)-1].Version, nil
+}
+
+type appliedMigration struct {
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+func loadAppliedMigrations(ctx context.Context, q interface {
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+}) (map[int]appliedMigration, error) {
+	rows, err := q.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[int]appliedMigration{}
+	for rows.Next() {
+		var v int
+		var a appliedMigration
+		if err := rows.Scan(&v, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		out[v] = a
+	}
+	return out, rows.Err()
+}
+
+// 마이그레이션 실행은 한 커넥션에서 세션 advisory lock을 잡고 진행 (여러 인스턴스 동시 실행 방지)
+func withMigrationLock(ctx context.Context, db *sql.DB, fn func(conn *sql.Conn) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock(hashtext('oracle_schema_migrations'))`); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext('oracle_schema_migrations'))`)
+	}()
+	if _, err := conn.ExecContext(ctx, schemaMigrationsDDL); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// MigrateUp : target 버전까지 미적용 마이그레이션을 순서대로 적용 (target<=0 이면 최신까지)
+func MigrateUp(ctx context.Context, db *sql.DB, target int) (applied []Migration, err error) {
+	ms, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	err = withMigrationLock(ctx, db, func(conn *sql.Conn) error {
+		done, err := loadAppliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range ms {
+			if target > 0 && m.Version > target {
+				break
+			}
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			if err := runMigrationTx(ctx, conn, m.Up, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx,
+					`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+					m.Version, m.Name, m.Checksum)
+				return err
+			}); err != nil {
+				return fmt.Errorf("migration %03d_%s up: %w", m.Version, m.Name, err)
+			}
+			applied = append(applied, m)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// MigrateDown : 가장 최근에 적용된 steps개 마이그레이션을 역순으로 되돌림
+func MigrateDown(ctx context.Context, db *sql.DB, steps int) (reverted []Migration, err error) {
+	if steps <= 0 {
+		return nil, nil
+	}
+	ms, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	byVer := make(map[int]Migration, len(ms))
+	for _, m := range ms {
+		byVer[m.Version] = m
+	}
+	err = withMigrationLock(ctx, db, func(conn *sql.Conn) error {
+		done, err := loadAppliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+		vers := make([]int, 0, len(done))
+		for v := range done {
+			vers = append(vers, v)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(vers)))
+		for i, v := range vers {
+			if i >= steps {
+				break
+			}
+			m, ok := byVer[v]
+			if !ok {
+				return fmt.Errorf("migration %d (%s) is applied but not known to this binary", v, done[v].Name)
+			}
+			if m.Down == "" {
+				return fmt.Errorf("migration %03d_%s has no down file", m.Version, m.Name)
+			}
+			if err := runMigrationTx(ctx, conn, m.Down, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+				return err
+			}); err != nil {
+				return fmt.Errorf("migration %03d_%s down: %w", m.Version, m.Name, err)
+			}
+			reverted = append(reverted, m)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+func runMigrationTx(ctx context.Context, conn *sql.Conn, body string, record func(tx *sql.Tx) error) (err error) {
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	// 인자 없는 Exec는 simple query 프로토콜이라 여러 문장을 한 번에 실행할 수 있다
+	if _, err = tx.ExecContext(ctx, body); err != nil {
+		return err
+	}
+	if err = record(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MigrationStatus : 포함된 마이그레이션 + DB 적용 이력 비교
+func MigrationStatus(ctx context.Context, db *sql.DB) ([]MigrationState, error) {
+	ms, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	var exists bool
+	if err := db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, err
+	}
+	done := map[int]appliedMigration{}
+	if exists {
+		if done, err = loadAppliedMigrations(ctx, db); err != nil {
+			return nil, err
+		}
+	}
+	out := make([]MigrationState, 0, len(ms))
+	for _, m := range ms {
+		st := MigrationState{Version: m.Version, Name: m.Name}
+		if a, ok := done[m.Version]; ok {
+			at := a.AppliedAt
+			st.Applied, st.AppliedAt = true, &at
+			st.Modified = a.Checksum != m.Checksum
+			delete(done, m.Version)
+		}
+		out = append(out, st)
+	}
+	for v, a := range done {
+		at := a.AppliedAt
+		out = append(out, MigrationState{Version: v, Name: a.Name, Applied: true, AppliedAt: &at, Unknown: true})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// CheckSchemaVersion : 서버 시작 전 확인
+// - 모든 포함 마이그레이션이 적용되어 있고, 모르는 버전이 없어야 한다
+// - 불일치면 에러 (운영자가 migrate up/down 으로 맞춘 뒤 재시작)
+func CheckSchemaVersion(ctx context.Context, db *sql.DB) error {
+	states, err := MigrationStatus(ctx, db)
+	if err != nil {
+		return err
+	}
+	var pending []int
+	for _, st := range states {
+		switch {
+		case st.Unknown:
+			return fmt.Errorf("schema has migration %d (%s) unknown to this binary; newer schema than code", st.Version, st.Name)
+		case !st.Applied:
+			pending = append(pending, st.Version)
+		case st.Modified:
+			return fmt.Errorf("migration %03d_%s was modified after it was applied", st.Version, st.Name)
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("schema is behind: %d pending migration(s) %v; run `migrate up`", len(pending), pending)
+	}
+	return nil
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS solar_archive;
+DROP TABLE IF EXISTS vote_counter;
+DROP TABLE IF EXISTS userData;
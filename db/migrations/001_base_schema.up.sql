@@ -0,0 +1,33 @@
+-- 001_base_schema.sql
+-- 오라클 기본 테이블: 사용자 등록 / 투표 카운터 / tx hash 보관
+-- 마이그레이션 도입 이전 DB에도 그대로 적용되도록 IF NOT EXISTS
+
+-- 1) 사용자(디바이스) 등록 (/connect)
+CREATE TABLE IF NOT EXISTS userData (
+  id         BIGSERIAL PRIMARY KEY,
+  node_id    TEXT NOT NULL,
+  device_id  TEXT NOT NULL,
+  password   TEXT NOT NULL,
+  public_key TEXT NOT NULL DEFAULT '',
+  address    TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_userdata_device ON userData (device_id);
+CREATE INDEX IF NOT EXISTS idx_userdata_node_device ON userData (node_id, device_id);
+CREATE INDEX IF NOT EXISTS idx_userdata_address ON userData (address);
+
+-- 2) 투표 카운터 (연속 참여 + 보상 누적, 선발 r_i 입력)
+CREATE TABLE IF NOT EXISTS vote_counter (
+  address   TEXT PRIMARY KEY,
+  last_time TIMESTAMPTZ,
+  count     DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+-- 3) 주소별 tx hash 보관 (hash UNIQUE: 중복 수신 무시)
+CREATE TABLE IF NOT EXISTS solar_archive (
+  id         BIGSERIAL PRIMARY KEY,
+  address    TEXT NOT NULL,
+  hash       TEXT NOT NULL UNIQUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_solar_archive_address ON solar_archive (address);
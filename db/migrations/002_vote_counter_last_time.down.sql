@@ -0,0 +1,2 @@
+-- last_date로 되돌리지 않는다 (코드는 last_time만 사용)
+SELECT 1;
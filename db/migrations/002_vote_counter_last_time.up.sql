@@ -0,0 +1,20 @@
+-- 002_vote_counter_last_time.sql
+-- 과거 스키마의 vote_counter.last_date를 last_time으로 통일, count를 실수형으로
+
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM information_schema.columns
+              WHERE table_name = 'vote_counter' AND column_name = 'last_date') THEN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+                WHERE table_name = 'vote_counter' AND column_name = 'last_time') THEN
+      UPDATE vote_counter
+         SET last_time = GREATEST(last_time, last_date::timestamptz);
+      ALTER TABLE vote_counter DROP COLUMN last_date;
+    ELSE
+      ALTER TABLE vote_counter RENAME COLUMN last_date TO last_time;
+    END IF;
+  END IF;
+END $$;
+
+ALTER TABLE vote_counter ALTER COLUMN last_time TYPE TIMESTAMPTZ USING last_time::timestamptz;
+ALTER TABLE vote_counter ALTER COLUMN count TYPE DOUBLE PRECISION USING count::double precision;
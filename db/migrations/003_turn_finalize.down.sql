@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS vote_counter_ledger;
+DROP TABLE IF EXISTS turn_result;
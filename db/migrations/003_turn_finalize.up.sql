@@ -28,3 +28,6 @@ CREATE TABLE IF NOT EXISTS vote_counter_ledger (
 -- 3) 조회 가속 인덱스
 CREATE INDEX IF NOT EXISTS idx_vcl_turn ON vote_counter_ledger (turn_id);
 CREATE INDEX IF NOT EXISTS idx_vcl_addr ON vote_counter_ledger (address);
+
+-- 4) 공정성 윈도우 조회 (turn_id, creator)
+CREATE INDEX IF NOT EXISTS idx_turn_result_turn_creator ON turn_result (turn_id, creator);
@@ -0,0 +1,12 @@
+-- 004_entity_link.sql
+-- 엔티티(운영자) 그룹핑: 관리자 수동 연결
+-- node_id / public_key 공유는 userData에서 자동 탐지하므로 별도 저장하지 않는다.
+
+CREATE TABLE IF NOT EXISTS entity_link (
+  address    TEXT PRIMARY KEY,
+  entity_id  TEXT NOT NULL,
+  note       TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_entity_link_entity ON entity_link (entity_id);
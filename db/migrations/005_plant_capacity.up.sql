@@ -0,0 +1,8 @@
+-- 005_plant_capacity.sql
+-- 발전소 설비 용량 레지스트리 (x_i 용량 정규화: kWh / 설치 kW)
+
+CREATE TABLE IF NOT EXISTS plant_capacity (
+  address     TEXT PRIMARY KEY,
+  capacity_kw DOUBLE PRECISION NOT NULL CHECK (capacity_kw > 0),
+  updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
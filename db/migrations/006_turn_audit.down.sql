@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS selection_denylist;
+DROP TABLE IF EXISTS turn_audit;
+ALTER TABLE turn_result DROP COLUMN IF EXISTS params;
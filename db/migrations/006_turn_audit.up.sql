@@ -0,0 +1,34 @@
+-- 006_turn_audit.sql
+-- 턴별 후보 감사 기록 + 선발 파라미터 스냅샷 + 선발 차단 목록
+
+ALTER TABLE turn_result ADD COLUMN IF NOT EXISTS params JSONB;
+
+-- 1) 후보별 적격성/가중치/확률 산출 내역 (제외된 후보는 eligible=false + reason)
+CREATE TABLE IF NOT EXISTS turn_audit (
+  turn_id     TEXT NOT NULL,
+  address     TEXT NOT NULL,
+  entity_id   TEXT NOT NULL DEFAULT '',
+  eligible    BOOLEAN NOT NULL,
+  reason      TEXT NOT NULL DEFAULT '',
+  energy_kwh  DOUBLE PRECISION NOT NULL DEFAULT 0,
+  vote_score  DOUBLE PRECISION NOT NULL DEFAULT 0,
+  x_i         DOUBLE PRECISION NOT NULL DEFAULT 0,
+  r_i         DOUBLE PRECISION NOT NULL DEFAULT 0,
+  w_base      DOUBLE PRECISION NOT NULL DEFAULT 0,
+  penalty     DOUBLE PRECISION NOT NULL DEFAULT 1,
+  penalty_r   INT NOT NULL DEFAULT 0,
+  w_i         DOUBLE PRECISION NOT NULL DEFAULT 0,
+  p_pre_cap   DOUBLE PRECISION NOT NULL DEFAULT 0,
+  p_i         DOUBLE PRECISION NOT NULL DEFAULT 0,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (turn_id, address)
+);
+CREATE INDEX IF NOT EXISTS idx_turn_audit_addr ON turn_audit (address);
+
+-- 2) 선발 차단 목록 (expires_at NULL = 무기한)
+CREATE TABLE IF NOT EXISTS selection_denylist (
+  address    TEXT PRIMARY KEY,
+  reason     TEXT NOT NULL DEFAULT '',
+  expires_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS contributor_disagreement;
+DROP TABLE IF EXISTS fullnode_registry;
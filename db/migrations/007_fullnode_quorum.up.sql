@@ -0,0 +1,23 @@
+-- 007_fullnode_quorum.sql
+-- 다중 풀노드 기여자 보고 quorum: 등록 풀노드 + 보고 불일치 기록
+
+CREATE TABLE IF NOT EXISTS fullnode_registry (
+  fullnode_id TEXT PRIMARY KEY,
+  public_key  TEXT NOT NULL DEFAULT '',
+  active      BOOLEAN NOT NULL DEFAULT true,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS contributor_disagreement (
+  turn_id       TEXT NOT NULL,
+  address       TEXT NOT NULL,
+  reported_by   TEXT[] NOT NULL,
+  reports       INT NOT NULL,
+  total_reports INT NOT NULL,
+  min_kwh       DOUBLE PRECISION NOT NULL,
+  max_kwh       DOUBLE PRECISION NOT NULL,
+  median_kwh    DOUBLE PRECISION NOT NULL,
+  dropped       BOOLEAN NOT NULL,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (turn_id, address)
+);
@@ -0,0 +1 @@
+ALTER TABLE turn_result DROP COLUMN IF EXISTS input_hash;
@@ -0,0 +1,4 @@
+-- 008_turn_input_hash.sql
+-- 같은 턴 재요청 감지: 선발 입력(기여자 목록) 해시
+
+ALTER TABLE turn_result ADD COLUMN IF NOT EXISTS input_hash TEXT NOT NULL DEFAULT '';
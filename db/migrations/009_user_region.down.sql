@@ -0,0 +1,3 @@
+ALTER TABLE turn_audit DROP COLUMN IF EXISTS geo_factor;
+ALTER TABLE turn_audit DROP COLUMN IF EXISTS region;
+DROP TABLE IF EXISTS user_region;
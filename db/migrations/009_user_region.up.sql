@@ -0,0 +1,11 @@
+-- 009_user_region.sql
+-- 사용자 등록 위치 (권역 다양성 선발용) + 감사 기록에 권역/보정 계수 추가
+
+CREATE TABLE IF NOT EXISTS user_region (
+  address    TEXT PRIMARY KEY,
+  location   TEXT NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE turn_audit ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '';
+ALTER TABLE turn_audit ADD COLUMN IF NOT EXISTS geo_factor DOUBLE PRECISION NOT NULL DEFAULT 1;
@@ -0,0 +1,4 @@
+-- 010_turn_snapshot.sql
+-- 선발 입력(vote_counter)을 읽은 DB 스냅샷 식별자 (txid_current_snapshot)
+
+ALTER TABLE turn_result ADD COLUMN IF NOT EXISTS snapshot_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trg_vote_counter_notify ON vote_counter;
+DROP FUNCTION IF EXISTS notify_vote_counter_changed();
@@ -0,0 +1,20 @@
+-- 011_vote_counter_notify.sql
+-- vote_counter 변경 알림 (인메모리 후보/점수 인덱스 동기화)
+
+CREATE OR REPLACE FUNCTION notify_vote_counter_changed() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    PERFORM pg_notify('vote_counter_changed',
+      json_build_object('address', OLD.address, 'count', 0, 'deleted', true)::text);
+    RETURN OLD;
+  END IF;
+  PERFORM pg_notify('vote_counter_changed',
+    json_build_object('address', NEW.address, 'count', NEW.count)::text);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_vote_counter_notify ON vote_counter;
+CREATE TRIGGER trg_vote_counter_notify
+AFTER INSERT OR UPDATE OR DELETE ON vote_counter
+FOR EACH ROW EXECUTE FUNCTION notify_vote_counter_changed();
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uq_turn_result_chain_seq;
+ALTER TABLE turn_result DROP COLUMN IF EXISTS prev_hash;
+ALTER TABLE turn_result DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE turn_result DROP COLUMN IF EXISTS chain_seq;
@@ -0,0 +1,7 @@
+-- 012_turn_chain.sql
+-- turn_result 해시 체인 (위·변조/삭제 감지)
+
+ALTER TABLE turn_result ADD COLUMN IF NOT EXISTS chain_seq BIGINT;
+ALTER TABLE turn_result ADD COLUMN IF NOT EXISTS content_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE turn_result ADD COLUMN IF NOT EXISTS prev_hash TEXT NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX IF NOT EXISTS uq_turn_result_chain_seq ON turn_result (chain_seq) WHERE chain_seq IS NOT NULL;
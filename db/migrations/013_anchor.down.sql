@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS anchor_leaf;
+DROP TABLE IF EXISTS anchor_epoch;
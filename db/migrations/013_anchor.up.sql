@@ -0,0 +1,23 @@
+-- 013_anchor.sql
+-- 턴/보상 Merkle root 온체인 앵커링
+
+CREATE TABLE IF NOT EXISTS anchor_epoch (
+  epoch_id     BIGSERIAL PRIMARY KEY,
+  merkle_root  TEXT NOT NULL,
+  leaf_count   INT NOT NULL,
+  status       TEXT NOT NULL DEFAULT 'pending', -- pending | submitted | failed
+  tx_hash      TEXT NOT NULL DEFAULT '',
+  rpc_code     INT NOT NULL DEFAULT 0,
+  rpc_log      TEXT NOT NULL DEFAULT '',
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  submitted_at TIMESTAMPTZ
+);
+CREATE TABLE IF NOT EXISTS anchor_leaf (
+  kind       TEXT NOT NULL,   -- turn | reward
+  ref        TEXT NOT NULL,   -- turn_id 또는 보상 항목 키
+  epoch_id   BIGINT NOT NULL REFERENCES anchor_epoch(epoch_id),
+  leaf_index INT NOT NULL,
+  leaf_hash  TEXT NOT NULL,
+  PRIMARY KEY (kind, ref)
+);
+CREATE INDEX IF NOT EXISTS idx_anchor_leaf_epoch ON anchor_leaf (epoch_id, leaf_index);
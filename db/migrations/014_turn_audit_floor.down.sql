@@ -0,0 +1 @@
+ALTER TABLE turn_audit DROP COLUMN IF EXISTS p_floor;
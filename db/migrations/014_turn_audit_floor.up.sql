@@ -0,0 +1,4 @@
+-- 014_turn_audit_floor.sql
+-- 확률 하한 보정량 (P-cap 전) 감사 기록
+
+ALTER TABLE turn_audit ADD COLUMN IF NOT EXISTS p_floor DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS gov_vote;
+DROP TABLE IF EXISTS gov_proposal;
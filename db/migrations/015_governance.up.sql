@@ -0,0 +1,23 @@
+-- 015_governance.sql
+-- 풀노드 서명 파라미터 변경 제안/투표
+
+CREATE TABLE IF NOT EXISTS gov_proposal (
+  proposal_id   TEXT PRIMARY KEY,          -- sha256(서명 대상 바이트)
+  proposer      TEXT NOT NULL,
+  params        JSONB NOT NULL,
+  activate_turn BIGINT NOT NULL,
+  nonce         TEXT NOT NULL DEFAULT '',
+  signature     TEXT NOT NULL,
+  status        TEXT NOT NULL DEFAULT 'open', -- open | approved | rejected | expired
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  decided_at    TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_gov_proposal_status ON gov_proposal (status, activate_turn);
+CREATE TABLE IF NOT EXISTS gov_vote (
+  proposal_id TEXT NOT NULL REFERENCES gov_proposal(proposal_id),
+  fullnode_id TEXT NOT NULL,
+  approve     BOOLEAN NOT NULL,
+  signature   TEXT NOT NULL,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (proposal_id, fullnode_id)
+);
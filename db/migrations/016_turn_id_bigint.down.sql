@@ -0,0 +1,3 @@
+ALTER TABLE contributor_disagreement ALTER COLUMN turn_id TYPE TEXT USING turn_id::text;
+ALTER TABLE turn_audit ALTER COLUMN turn_id TYPE TEXT USING turn_id::text;
+ALTER TABLE turn_result ALTER COLUMN turn_id TYPE TEXT USING turn_id::text;
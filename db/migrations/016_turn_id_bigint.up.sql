@@ -0,0 +1,20 @@
+-- 016_turn_id_bigint.sql
+-- turn_id를 정수(BIGINT)로 통일: 공정성/권역 윈도우 조회(turn_id > $1 - $2)와 정렬이 숫자 기준으로 동작
+-- 숫자가 아닌 turn_id가 남아 있으면 변환하지 않고 중단한다 (수동 정리 필요)
+
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM turn_result WHERE turn_id::text !~ '^-?[0-9]+$') THEN
+    RAISE EXCEPTION 'turn_result has non-numeric turn_id rows; clean them up before migrating';
+  END IF;
+  IF EXISTS (SELECT 1 FROM turn_audit WHERE turn_id::text !~ '^-?[0-9]+$') THEN
+    RAISE EXCEPTION 'turn_audit has non-numeric turn_id rows; clean them up before migrating';
+  END IF;
+  IF EXISTS (SELECT 1 FROM contributor_disagreement WHERE turn_id::text !~ '^-?[0-9]+$') THEN
+    RAISE EXCEPTION 'contributor_disagreement has non-numeric turn_id rows; clean them up before migrating';
+  END IF;
+END $$;
+
+ALTER TABLE turn_result ALTER COLUMN turn_id TYPE BIGINT USING turn_id::bigint;
+ALTER TABLE turn_audit ALTER COLUMN turn_id TYPE BIGINT USING turn_id::bigint;
+ALTER TABLE contributor_disagreement ALTER COLUMN turn_id TYPE BIGINT USING turn_id::bigint;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reward_credit;
+DROP TABLE IF EXISTS reward_round;
@@ -0,0 +1,34 @@
+-- 017_reward_ledger.sql
+-- 검증자 보상 라운드 + 주소별 적립 원장 (vote_counter.count 재구성/감사용)
+
+-- 1) 보상 요청(라운드) 단위 입력/산출 스냅샷
+CREATE TABLE IF NOT EXISTS reward_round (
+  round_id       BIGSERIAL PRIMARY KEY,
+  fullnode_id    TEXT NOT NULL,
+  request_ts     TEXT NOT NULL DEFAULT '',   -- 요청 메시지의 timestamp 원문
+  n_participants INT NOT NULL,               -- n
+  n_total        INT NOT NULL,               -- N
+  participation  DOUBLE PRECISION NOT NULL,  -- r = n / N
+  r0             DOUBLE PRECISION NOT NULL,  -- R0 스냅샷
+  policy         JSONB NOT NULL,
+  base_reward    DOUBLE PRECISION NOT NULL,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_reward_round_fullnode ON reward_round (fullnode_id, created_at);
+
+-- 2) 주소별 적립 (before -> [초기화] -> +increment -> +reward -> after)
+CREATE TABLE IF NOT EXISTS reward_credit (
+  round_id         BIGINT NOT NULL REFERENCES reward_round(round_id),
+  address          TEXT NOT NULL,
+  before_count     DOUBLE PRECISION NOT NULL,
+  before_last_time TIMESTAMPTZ,
+  reset            BOOLEAN NOT NULL,          -- 미참여 기간 초과로 0부터 다시 시작
+  increment        DOUBLE PRECISION NOT NULL, -- 참여 카운트 (+1)
+  reward           DOUBLE PRECISION NOT NULL,
+  after_count      DOUBLE PRECISION NOT NULL,
+  after_last_time  TIMESTAMPTZ NOT NULL,
+  content_hash     TEXT NOT NULL,             -- 앵커링 잎 입력
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (round_id, address)
+);
+CREATE INDEX IF NOT EXISTS idx_reward_credit_address ON reward_credit (address, round_id);
@@ -0,0 +1,2 @@
+ALTER TABLE reward_round DROP COLUMN IF EXISTS published_at;
+ALTER TABLE reward_round DROP COLUMN IF EXISTS policy_version;
@@ -0,0 +1,5 @@
+-- 018_reward_round_publish.sql
+-- 보상 라운드 정책 버전 + 풀노드 결과 송신 시각
+
+ALTER TABLE reward_round ADD COLUMN IF NOT EXISTS policy_version TEXT NOT NULL DEFAULT '';
+ALTER TABLE reward_round ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+ALTER TABLE reward_credit DROP COLUMN IF EXISTS multiplier;
+ALTER TABLE reward_credit DROP COLUMN IF EXISTS streak;
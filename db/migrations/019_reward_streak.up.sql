@@ -0,0 +1,5 @@
+-- 019_reward_streak.sql
+-- 주소별 연속 참여 횟수 + 적용 배율 (보상 곡선/연속 참여 보너스)
+
+ALTER TABLE reward_credit ADD COLUMN IF NOT EXISTS streak INT NOT NULL DEFAULT 0;
+ALTER TABLE reward_credit ADD COLUMN IF NOT EXISTS multiplier DOUBLE PRECISION NOT NULL DEFAULT 1;
@@ -0,0 +1 @@
+ALTER TABLE reward_round DROP COLUMN IF EXISTS n_source;
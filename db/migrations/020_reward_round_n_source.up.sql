@@ -0,0 +1,4 @@
+-- 020_reward_round_n_source.sql
+-- 참여율 분모 N 산출 정의 (예: "eligible:active=30d", "config")
+
+ALTER TABLE reward_round ADD COLUMN IF NOT EXISTS n_source TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_reward_round_emission_epoch;
+ALTER TABLE reward_round DROP COLUMN IF EXISTS total_reward;
+ALTER TABLE reward_round DROP COLUMN IF EXISTS emission_scale;
+ALTER TABLE reward_round DROP COLUMN IF EXISTS emission_spent;
+ALTER TABLE reward_round DROP COLUMN IF EXISTS emission_budget;
+ALTER TABLE reward_round DROP COLUMN IF EXISTS emission_epoch;
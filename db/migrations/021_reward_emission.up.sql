@@ -0,0 +1,9 @@
+-- 021_reward_emission.sql
+-- epoch 보상 예산: 라운드별 epoch/예산/사용량/비례 축소 배율 기록
+
+ALTER TABLE reward_round ADD COLUMN IF NOT EXISTS emission_epoch BIGINT;                          -- NULL = 예산 미적용
+ALTER TABLE reward_round ADD COLUMN IF NOT EXISTS emission_budget DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE reward_round ADD COLUMN IF NOT EXISTS emission_spent DOUBLE PRECISION NOT NULL DEFAULT 0;  -- 이 라운드 이전 사용량
+ALTER TABLE reward_round ADD COLUMN IF NOT EXISTS emission_scale DOUBLE PRECISION NOT NULL DEFAULT 1;  -- 예산 초과 시 < 1
+ALTER TABLE reward_round ADD COLUMN IF NOT EXISTS total_reward DOUBLE PRECISION NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_reward_round_emission_epoch ON reward_round (emission_epoch);
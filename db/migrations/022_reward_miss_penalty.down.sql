@@ -0,0 +1,16 @@
+-- 미참여(miss) 기록이 있으면 되돌리지 않는다: 그 행을 지우면 vote_counter에 이미 반영된 차감과
+-- 원장(reward_credit), 앵커링된 잎이 어긋난다 (필요하면 백업 후 수동 정리)
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM reward_credit WHERE kind <> 'credit' OR after_last_time IS NULL) THEN
+    RAISE EXCEPTION 'reward_credit has miss entries; they are part of the ledger and cannot be dropped by migrate down';
+  END IF;
+END $$;
+
+DROP INDEX IF EXISTS idx_reward_credit_ineligible;
+ALTER TABLE reward_credit ALTER COLUMN after_last_time SET NOT NULL;
+ALTER TABLE reward_credit DROP COLUMN IF EXISTS ineligible_until;
+ALTER TABLE reward_credit DROP COLUMN IF EXISTS miss_streak;
+ALTER TABLE reward_credit DROP COLUMN IF EXISTS kind;
+ALTER TABLE reward_round DROP COLUMN IF EXISTS n_missed;
+ALTER TABLE reward_round DROP COLUMN IF EXISTS n_expected;
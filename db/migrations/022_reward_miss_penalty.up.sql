@@ -0,0 +1,15 @@
+-- 022_reward_miss_penalty.sql
+-- 기대 검증자 집합 대비 미참여(miss) 기록 + 점수 차감/일시 선발 제외 (원장에 기록)
+
+ALTER TABLE reward_round ADD COLUMN IF NOT EXISTS n_expected INT NOT NULL DEFAULT 0; -- 풀노드가 보낸 기대 검증자 수 (0 = 미제공)
+ALTER TABLE reward_round ADD COLUMN IF NOT EXISTS n_missed INT NOT NULL DEFAULT 0;
+
+-- kind: credit (참여 적립) | miss (미참여, reward <= 0 = 차감)
+ALTER TABLE reward_credit ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'credit';
+ALTER TABLE reward_credit ADD COLUMN IF NOT EXISTS miss_streak INT NOT NULL DEFAULT 0;
+ALTER TABLE reward_credit ADD COLUMN IF NOT EXISTS ineligible_until TIMESTAMPTZ;
+-- miss는 last_time을 바꾸지 않으며, 카운터 행이 없던 주소는 NULL
+ALTER TABLE reward_credit ALTER COLUMN after_last_time DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_reward_credit_ineligible ON reward_credit (address, ineligible_until)
+  WHERE ineligible_until IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE reward_credit DROP COLUMN IF EXISTS r0;
+ALTER TABLE reward_credit DROP COLUMN IF EXISTS region;
+ALTER TABLE reward_round DROP COLUMN IF EXISTS r0_regions;
@@ -0,0 +1,6 @@
+-- 023_reward_regional_r0.sql
+-- 권역별 R0: 라운드에 권역 R0 표 스냅샷, 주소별로 적용한 권역/R0 기록
+
+ALTER TABLE reward_round ADD COLUMN IF NOT EXISTS r0_regions JSONB;            -- NULL = 전국 R0 단일 적용
+ALTER TABLE reward_credit ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '';  -- 등록 권역 ('' = 미적용/미등록)
+ALTER TABLE reward_credit ADD COLUMN IF NOT EXISTS r0 DOUBLE PRECISION;          -- 이 주소에 적용한 R0 (NULL = 이전 기록, reward_round.r0)
@@ -0,0 +1,9 @@
+-- 024_device_owner.sql
+-- 디바이스 소유자 레지스트리 (엔티티 그룹핑 키 device_owner)
+
+CREATE TABLE IF NOT EXISTS device_owner (
+  device_id  TEXT PRIMARY KEY,
+  owner_id   TEXT NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_device_owner_owner ON device_owner (owner_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_reward_round_unpublished;
@@ -0,0 +1,5 @@
+-- 025_reward_round_unpublished.sql
+-- 미송신 보상 라운드 재송신 스윕 (published_at IS NULL) 조회 가속
+
+CREATE INDEX IF NOT EXISTS idx_reward_round_unpublished ON reward_round (created_at)
+  WHERE published_at IS NULL;
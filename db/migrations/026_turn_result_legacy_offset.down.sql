@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_turn_result_current;
+ALTER TABLE turn_result DROP COLUMN IF EXISTS legacy_offset;
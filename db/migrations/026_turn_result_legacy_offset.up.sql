@@ -0,0 +1,10 @@
+-- 026_turn_result_legacy_offset.sql
+-- 턴 키가 풀노드 height로 고정되기 전 행 표시
+-- 이전에는 turn_id가 height 또는 Kafka offset이었고 행만으로는 구분할 수 없으므로 기존 행 전부를 legacy로 본다.
+-- legacy 행은 재송신(replay)·공정성/권역 창·최근 턴 조회에서 제외하며,
+-- 같은 turn_id의 새 height는 결정하지 않고 거부한다 (finalize 오류, 수동 정리 필요)
+
+ALTER TABLE turn_result ADD COLUMN IF NOT EXISTS legacy_offset BOOLEAN NOT NULL DEFAULT false;
+UPDATE turn_result SET legacy_offset = true;
+
+CREATE INDEX IF NOT EXISTS idx_turn_result_current ON turn_result (turn_id) WHERE NOT legacy_offset;
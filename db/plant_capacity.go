@@ -0,0 +1,75 @@
+// oracle/db/plant_capacity.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// 발전소(주소별) 설비 용량 레지스트리 — x_i 용량 정규화(kWh/kW)에 사용
+type PlantCapacity struct {
+	Address    string  `json:"address"`
+	CapacityKw float64 `json:"capacity_kw"`
+	UpdatedAt  string  `json:"updated_at,omitempty"`
+}
+
+func UpsertPlantCapacity(ctx context.Context, db *sql.DB, address string, capacityKw float64) error {
+	if address == "" {
+		return fmt.Errorf("UpsertPlantCapacity: empty address")
+	}
+	if capacityKw <= 0 {
+		return fmt.Errorf("UpsertPlantCapacity: capacity_kw must be > 0 (got %.4f)", capacityKw)
+	}
+	const q = `
+INSERT INTO plant_capacity (address, capacity_kw, updated_at)
+VALUES ($1, $2, now())
+ON CONFLICT (address) DO UPDATE
+SET capacity_kw = EXCLUDED.capacity_kw,
+    updated_at  = now();`
+	_, err := db.ExecContext(ctx, q, address, capacityKw)
+	return err
+}
+
+// 여러 address의 설비 용량 조회 (등록 안 된 주소는 맵에 없음)
+func GetPlantCapacities(ctx context.Context, db *sql.DB, addresses []string) (map[string]float64, error) {
+	out := make(map[string]float64, len(addresses))
+	if len(addresses) == 0 {
+		return out, nil
+	}
+	rows, err := db.QueryContext(ctx,
+		`SELECT address, capacity_kw FROM plant_capacity WHERE address = ANY($1)`, pq.Array(addresses))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a string
+		var c float64
+		if err := rows.Scan(&a, &c); err != nil {
+			return nil, err
+		}
+		out[a] = c
+	}
+	return out, rows.Err()
+}
+
+func ListPlantCapacities(ctx context.Context, db *sql.DB) ([]PlantCapacity, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT address, capacity_kw, to_char(updated_at, 'YYYY-MM-DD"T"HH24:MI:SSOF') FROM plant_capacity ORDER BY address`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]PlantCapacity, 0)
+	for rows.Next() {
+		var p PlantCapacity
+		if err := rows.Scan(&p.Address, &p.CapacityKw, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,492 @@
+// oracle/db/reward_ledger.go
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// 보상 라운드 (검증자 보상 요청 1건)
+type RewardRound struct {
+	RoundID       int64           `json:"round_id"`
+	FullnodeID    string          `json:"fullnode_id"`
+	RequestTS     string          `json:"request_ts"`
+	Participants  int             `json:"n"`
+	Total         int             `json:"N"`
+	TotalSource   string          `json:"n_source"` // N 산출 정의
+	Rate          float64         `json:"r"`
+	R0            float64         `json:"r0"`
+	Policy        json.RawMessage `json:"policy"`
+	PolicyVersion string          `json:"policy_version"` // 정책 JSON 해시 (풀노드 송신 메시지와 동일)
+	BaseReward    float64         `json:"base_reward"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at,omitempty"`
+
+	// epoch 발행 예산 (EmissionEpoch nil = 예산 미적용)
+	EmissionEpoch  *int64  `json:"emission_epoch,omitempty"`
+	EmissionBudget float64 `json:"emission_budget"`
+	EmissionSpent  float64 `json:"emission_spent"` // 이 라운드 이전 사용량
+	EmissionScale  float64 `json:"emission_scale"` // 예산 초과 시 비례 축소 배율 (1 = 미축소)
+	TotalReward    float64 `json:"total_reward"`
+
+	// 기대 검증자 집합 대비 미참여
+	Expected int `json:"n_expected"` // 풀노드가 보낸 기대 검증자 수 (0 = 미제공)
+	Missed   int `json:"n_missed"`
+
+	// 권역별 R0 표 스냅샷 (nil = 전국 R0 단일 적용)
+	R0Regions json.RawMessage `json:"r0_regions,omitempty"`
+}
+
+// 원장 항목 종류
+const (
+	CreditKindCredit = "credit" // 참여 적립
+	CreditKindMiss   = "miss"   // 기대 검증자 미참여 (reward <= 0 = 차감)
+)
+
+// 주소별 적립 기록: after = (reset ? 0 : before) + increment + reward
+type RewardCredit struct {
+	RoundID        int64      `json:"round_id"`
+	Address        string     `json:"address"`
+	Kind           string     `json:"kind"`
+	Region         string     `json:"region,omitempty"` // 권역별 R0 적용 시 등록 권역
+	R0             float64    `json:"r0"`               // 이 주소에 적용한 R0 (miss = 0)
+	BeforeCount    float64    `json:"before_count"`
+	BeforeLastTime *time.Time `json:"before_last_time,omitempty"`
+	Reset          bool       `json:"reset"`
+	Increment      float64    `json:"increment"`
+	Streak         int        `json:"streak"`     // 이번 라운드 포함 연속 참여 횟수
+	Multiplier     float64    `json:"multiplier"` // 주소별 배율 (연속 참여 보너스)
+	Reward         float64    `json:"reward"`     // BaseReward × Multiplier
+	AfterCount     float64    `json:"after_count"`
+	AfterLastTime  time.Time  `json:"after_last_time"` // miss는 before_last_time 유지 (없으면 zero = NULL)
+	MissStreak     int        `json:"miss_streak"`     // 이번 라운드 포함 연속 미참여 횟수
+	// 연속 미참여로 선발 후보에서 제외되는 시각까지 (nil = 제외 없음)
+	IneligibleUntil *time.Time `json:"ineligible_until,omitempty"`
+	ContentHash     string     `json:"content_hash"`
+}
+
+// 라운드 트랜잭션 안에서 잠근 vote_counter 행 (없던 주소는 Count=0, LastTime NULL)
+type VoteCounterState struct {
+	Address  string
+	Count    float64
+	LastTime sql.NullTime
+	Streak   int // 직전 적립의 연속 참여 횟수 (기록 없거나 풀노드 직전 라운드와 공백이면 0)
+	Missed   int // 직전 항목의 연속 미참여 횟수
+}
+
+func rewardCreditHash(c RewardCredit) string {
+	before := ""
+	if c.BeforeLastTime != nil {
+		before = c.BeforeLastTime.UTC().Format(time.RFC3339Nano)
+	}
+	ts := func(t *time.Time) string {
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+	s := fmt.Sprintf("v3\nround=%d\naddress=%s\nkind=%s\nbefore=%s\nbefore_last=%s\nreset=%t\nincrement=%s\nstreak=%d\nmultiplier=%s\nreward=%s\nafter=%s\nafter_last=%s\nmiss_streak=%d\nineligible_until=%s\nregion=%s\nr0=%s\n",
+		c.RoundID, c.Address, c.Kind, f(c.BeforeCount), before, c.Reset, f(c.Increment), c.Streak, f(c.Multiplier), f(c.Reward),
+		f(c.AfterCount), ts(&c.AfterLastTime), c.MissStreak, ts(c.IneligibleUntil), c.Region, f(c.R0))
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordRewardRoundTx
+// - reward_round 저장 + 대상 vote_counter 행 잠금(주소 정렬) + 갱신 + reward_credit 기록을 한 트랜잭션으로
+// - 주소 수와 무관하게 고정 왕복 (빈 행 INSERT / 잠금 SELECT / streak / round INSERT / unnest UPDATE / unnest INSERT)
+// - EmissionEpoch가 있으면 epoch 남은 예산 안으로 보상을 비례 축소
+// - credit(st) 가 잠긴 상태를 받아 적립 내용을 결정 (RoundID/Address/Before*/ContentHash는 여기서 채움)
+// - credit의 AfterCount는 Reward를 포함해야 한다 (예산 축소 시 같은 차이만큼 조정)
+// - missed(기대 검증자 중 미참여)는 miss(st)로 차감/제외 항목을 만든다 (예산 대상 아님, 카운터 행을 새로 만들지 않음)
+func RecordRewardRoundTx(ctx context.Context, db *sql.DB, round RewardRound, addrs, missed []string,
+	credit, miss func(st VoteCounterState) RewardCredit) (_ RewardRound, credits []RewardCredit, err error) {
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return round, nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	states, err := lockVoteCountersTx(ctx, tx, round.FullnodeID, addrs, missed)
+	if err != nil {
+		return round, nil, err
+	}
+	missSet := make(map[string]struct{}, len(missed))
+	for _, a := range missed {
+		missSet[a] = struct{}{}
+	}
+
+	credits = make([]RewardCredit, 0, len(states))
+	total := 0.0
+	for _, st := range states {
+		_, isMiss := missSet[st.Address]
+		var c RewardCredit
+		if isMiss {
+			c = miss(st)
+			c.Kind = CreditKindMiss
+		} else {
+			c = credit(st)
+			c.Kind = CreditKindCredit
+			total += c.Reward
+		}
+		c.Address, c.BeforeCount = st.Address, st.Count
+		if st.LastTime.Valid {
+			t := st.LastTime.Time
+			c.BeforeLastTime = &t
+		}
+		if !c.AfterLastTime.IsZero() {
+			c.AfterLastTime = c.AfterLastTime.UTC().Truncate(time.Microsecond)
+		}
+		if c.IneligibleUntil != nil {
+			// timestamptz 정밀도로 맞춰야 저장 후 다시 읽은 행으로 content_hash를 재계산할 수 있다
+			t := c.IneligibleUntil.UTC().Truncate(time.Microsecond)
+			c.IneligibleUntil = &t
+		}
+		credits = append(credits, c)
+	}
+
+	// epoch 예산: 남은 예산을 넘으면 이번 라운드 보상을 비례 축소 (같은 락 안에서 사용량 확인)
+	round.EmissionScale = 1
+	if round.EmissionEpoch != nil {
+		if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('reward_emission'))`); err != nil {
+			return round, nil, err
+		}
+		if round.EmissionSpent, err = emissionSpentTx(ctx, tx, *round.EmissionEpoch); err != nil {
+			return round, nil, err
+		}
+		remaining := math.Max(0, round.EmissionBudget-round.EmissionSpent)
+		if total > remaining {
+			round.EmissionScale = 0
+			if total > 0 {
+				round.EmissionScale = remaining / total
+			}
+			total = 0
+			for i := range credits {
+				if credits[i].Kind != CreditKindCredit {
+					continue
+				}
+				scaled := credits[i].Reward * round.EmissionScale
+				credits[i].AfterCount -= credits[i].Reward - scaled
+				credits[i].Reward = scaled
+				total += scaled
+			}
+		}
+	}
+	round.TotalReward = total
+	round.Missed = len(missSet)
+
+	policy := round.Policy
+	if len(policy) == 0 {
+		policy = json.RawMessage(`{}`)
+	}
+	var r0Regions []byte
+	if len(round.R0Regions) > 0 {
+		r0Regions = round.R0Regions
+	}
+	if err = tx.QueryRowContext(ctx, `
+INSERT INTO reward_round (fullnode_id, request_ts, n_participants, n_total, n_source, participation, r0, policy,
+                          policy_version, base_reward, emission_epoch, emission_budget, emission_spent,
+                          emission_scale, total_reward, n_expected, n_missed, r0_regions)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+RETURNING round_id, created_at`,
+		round.FullnodeID, round.RequestTS, round.Participants, round.Total, round.TotalSource, round.Rate, round.R0,
+		[]byte(policy), round.PolicyVersion, round.BaseReward, round.EmissionEpoch, round.EmissionBudget,
+		round.EmissionSpent, round.EmissionScale, round.TotalReward, round.Expected, round.Missed, r0Regions).Scan(&round.RoundID, &round.CreatedAt); err != nil {
+		return round, nil, err
+	}
+	if len(credits) == 0 {
+		return round, credits, nil
+	}
+	for i := range credits {
+		credits[i].RoundID = round.RoundID
+		credits[i].ContentHash = rewardCreditHash(credits[i])
+	}
+	if err = writeRewardCreditsTx(ctx, tx, credits); err != nil {
+		return round, nil, err
+	}
+	return round, credits, nil
+}
+
+// 대상 vote_counter 행 잠금 + 직전 streak (주소 정렬 결과)
+// - 참여자(create)는 처음 보는 주소면 빈 행을 만든 뒤 함께 잠근다
+// - 미참여자(lockOnly)는 있는 행만 잠그고, 없으면 Count=0 상태로 돌려준다
+// - 전체를 주소 순서로 한 번에 잠가 교착 방지
+func lockVoteCountersTx(ctx context.Context, tx *sql.Tx, fullnodeID string, create, lockOnly []string) ([]VoteCounterState, error) {
+	all := make([]string, 0, len(create)+len(lockOnly))
+	all = append(append(all, create...), lockOnly...)
+	if len(all) == 0 {
+		return nil, nil
+	}
+	sort.Strings(all)
+	if len(create) > 0 {
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO vote_counter (address, last_time, count)
+SELECT a, NULL, 0 FROM unnest($1::text[]) AS a
+ON CONFLICT (address) DO NOTHING`, pq.Array(create)); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := tx.QueryContext(ctx, `
+SELECT address, count, last_time FROM vote_counter
+ WHERE address = ANY($1)
+ ORDER BY address
+ FOR UPDATE`, pq.Array(all))
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]VoteCounterState, len(all))
+	for rows.Next() {
+		var st VoteCounterState
+		if err := rows.Scan(&st.Address, &st.Count, &st.LastTime); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		found[st.Address] = st
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	states := make([]VoteCounterState, 0, len(all))
+	for _, a := range all {
+		st, ok := found[a]
+		if !ok {
+			st = VoteCounterState{Address: a}
+		}
+		states = append(states, st)
+	}
+	if err := loadPrevStreaksTx(ctx, tx, fullnodeID, all, states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// vote_counter 갱신 + reward_credit 기록을 집합 연산 2회로 (주소별 왕복 없음)
+func writeRewardCreditsTx(ctx context.Context, tx *sql.Tx, credits []RewardCredit) error {
+	n := len(credits)
+	var (
+		addr, hash           = make([]string, n), make([]string, n)
+		before, after        = make([]float64, n), make([]float64, n)
+		incr, reward         = make([]float64, n), make([]float64, n)
+		mult                 = make([]float64, n)
+		streak, missStreak   = make([]int64, n), make([]int64, n)
+		reset                = make([]bool, n)
+		kind                 = make([]string, n)
+		beforeLast, lastTime = make([]*string, n), make([]*string, n)
+		region               = make([]string, n)
+		r0                   = make([]float64, n)
+		until                = make([]*string, n)
+	)
+	for i, c := range credits {
+		if !c.AfterLastTime.IsZero() {
+			lt := c.AfterLastTime.Format(time.RFC3339Nano)
+			lastTime[i] = &lt
+		}
+		if c.IneligibleUntil != nil {
+			u := c.IneligibleUntil.UTC().Format(time.RFC3339Nano)
+			until[i] = &u
+		}
+		kind[i], missStreak[i] = c.Kind, int64(c.MissStreak)
+		region[i], r0[i] = c.Region, c.R0
+		if c.BeforeLastTime != nil {
+			bl := c.BeforeLastTime.UTC().Format(time.RFC3339Nano)
+			beforeLast[i] = &bl
+		}
+		addr[i], hash[i] = c.Address, c.ContentHash
+		before[i], after[i] = c.BeforeCount, c.AfterCount
+		incr[i], reward[i], reset[i] = c.Increment, c.Reward, c.Reset
+		streak[i], mult[i] = int64(c.Streak), c.Multiplier
+	}
+	if _, err := tx.ExecContext(ctx, `
+UPDATE vote_counter v
+   SET count = u.after_count, last_time = COALESCE(u.after_last_time, v.last_time)
+  FROM unnest($1::text[], $2::float8[], $3::timestamptz[]) AS u(address, after_count, after_last_time)
+ WHERE v.address = u.address`,
+		pq.Array(addr), pq.Array(after), pq.Array(lastTime)); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO reward_credit
+(round_id, address, before_count, before_last_time, reset, increment, reward, after_count, after_last_time,
+ content_hash, streak, multiplier, kind, miss_streak, ineligible_until, region, r0)
+SELECT $1, u.*
+  FROM unnest($2::text[], $3::float8[], $4::timestamptz[], $5::bool[], $6::float8[], $7::float8[],
+              $8::float8[], $9::timestamptz[], $10::text[], $11::int[], $12::float8[],
+              $13::text[], $14::int[], $15::timestamptz[], $16::text[], $17::float8[]) AS u`,
+		credits[0].RoundID, pq.Array(addr), pq.Array(before), pq.Array(beforeLast), pq.Array(reset),
+		pq.Array(incr), pq.Array(reward), pq.Array(after), pq.Array(lastTime), pq.Array(hash),
+		pq.Array(streak), pq.Array(mult), pq.Array(kind), pq.Array(missStreak), pq.Array(until),
+		pq.Array(region), pq.Array(r0))
+	return err
+}
+
+func emissionSpentTx(ctx context.Context, tx *sql.Tx, epoch int64) (float64, error) {
+	var spent float64
+	err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(total_reward), 0) FROM reward_round WHERE emission_epoch = $1`, epoch).Scan(&spent)
+	return spent, err
+}
+
+// epoch의 보상 사용량 (API/메트릭용)
+func GetEmissionSpent(ctx context.Context, db *sql.DB, epoch int64) (spent float64, rounds int, err error) {
+	err = db.QueryRowContext(ctx, `
+SELECT COALESCE(SUM(total_reward), 0), COUNT(*) FROM reward_round WHERE emission_epoch = $1`, epoch).Scan(&spent, &rounds)
+	return spent, rounds, err
+}
+
+// CountEligibleValidators : 보상 참여율 분모 N
+// - userData에 등록된 주소 (nodeID가 있으면 그 풀노드에 등록된 주소만)
+// - activeSince가 0이 아니면 그 이후 보상에 참여(vote_counter.last_time)했거나 신규 등록한 주소만
+// - 이번 요청 참여자(participants)는 항상 포함 (n <= N 보장)
+func CountEligibleValidators(ctx context.Context, db *sql.DB, activeSince time.Time, nodeID string, participants []string) (int, error) {
+	var since sql.NullTime
+	if !activeSince.IsZero() {
+		since = sql.NullTime{Time: activeSince, Valid: true}
+	}
+	var n int
+	err := db.QueryRowContext(ctx, `
+SELECT COUNT(*) FROM (
+  SELECT u.address
+    FROM userData u
+    LEFT JOIN vote_counter v ON v.address = u.address
+   WHERE u.address <> ''
+     AND ($2 = '' OR u.node_id = $2)
+     AND ($1::timestamptz IS NULL OR v.last_time >= $1 OR u.created_at >= $1)
+  UNION
+  SELECT a FROM unnest($3::text[]) AS a WHERE a <> ''
+) s`, since, nodeID, pq.Array(participants)).Scan(&n)
+	return n, err
+}
+
+// 주소별 가장 최근 항목의 연속 참여/미참여 횟수
+// - 그 항목이 이 풀노드의 직전 라운드보다 앞이면(사이 라운드에 적립도 미참여 기록도 없음) 연속이 끊긴 것으로 보고 0부터
+// - 다른 풀노드 라운드에서 더 최근에 기록된 주소는 끊기지 않은 것으로 본다
+func loadPrevStreaksTx(ctx context.Context, tx *sql.Tx, fullnodeID string, addrs []string, states []VoteCounterState) error {
+	rows, err := tx.QueryContext(ctx, `
+SELECT DISTINCT ON (c.address) c.address, c.round_id, c.streak, c.miss_streak,
+       (SELECT COALESCE(MAX(round_id), 0) FROM reward_round WHERE fullnode_id = $2) AS prev_round
+  FROM reward_credit c
+ WHERE c.address = ANY($1)
+ ORDER BY c.address, c.round_id DESC`, pq.Array(addrs), fullnodeID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	type prevStreak struct{ streak, missed int }
+	prev := make(map[string]prevStreak, len(addrs))
+	for rows.Next() {
+		var a string
+		var k prevStreak
+		var lastRound, prevRound int64
+		if err := rows.Scan(&a, &lastRound, &k.streak, &k.missed, &prevRound); err != nil {
+			return err
+		}
+		if lastRound < prevRound {
+			continue // 직전 라운드 공백: 연속 초기화
+		}
+		prev[a] = k
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for i := range states {
+		k := prev[states[i].Address]
+		states[i].Streak, states[i].Missed = k.streak, k.missed
+	}
+	return nil
+}
+
+// 송신되지 않은 라운드 (재송신 대상): 생성 후 minAge 경과, maxAge 이내, 오래된 순
+func ListUnpublishedRewardRounds(ctx context.Context, db *sql.DB, minAge, maxAge time.Duration, limit int) ([]RewardRound, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT round_id, fullnode_id, request_ts, policy_version, base_reward, created_at
+  FROM reward_round
+ WHERE published_at IS NULL
+   AND created_at <= now() - make_interval(secs => $1)
+   AND created_at >  now() - make_interval(secs => $2)
+ ORDER BY round_id
+ LIMIT $3`, minAge.Seconds(), maxAge.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]RewardRound, 0)
+	for rows.Next() {
+		var r RewardRound
+		if err := rows.Scan(&r.RoundID, &r.FullnodeID, &r.RequestTS, &r.PolicyVersion, &r.BaseReward, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// 라운드의 주소별 적립/차감 (재송신 본문용)
+func ListRewardRoundCredits(ctx context.Context, db *sql.DB, roundID int64) ([]RewardCredit, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT address, kind, reward FROM reward_credit WHERE round_id = $1 ORDER BY address`, roundID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]RewardCredit, 0)
+	for rows.Next() {
+		c := RewardCredit{RoundID: roundID}
+		if err := rows.Scan(&c.Address, &c.Kind, &c.Reward); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// 풀노드로 결과 송신 완료 표시 (published_at NULL = 미송신)
+func MarkRewardRoundPublished(ctx context.Context, db *sql.DB, roundID int64) error {
+	_, err := db.ExecContext(ctx, `UPDATE reward_round SET published_at = now() WHERE round_id = $1`, roundID)
+	return err
+}
+
+// 아직 앵커링되지 않은 보상 적립 (round_id, address 순). ref = "<round_id>:<address>"
+func FetchUnanchoredRewardLeaves(ctx context.Context, db *sql.DB, limit int) ([]AnchorLeaf, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	rows, err := db.QueryContext(ctx, `
+SELECT c.round_id::text || ':' || c.address, c.content_hash
+  FROM reward_credit c
+ WHERE NOT EXISTS (SELECT 1 FROM anchor_leaf a
+                    WHERE a.kind = 'reward' AND a.ref = c.round_id::text || ':' || c.address)
+ ORDER BY c.round_id, c.address
+ LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]AnchorLeaf, 0)
+	for rows.Next() {
+		l := AnchorLeaf{Kind: "reward"}
+		if err := rows.Scan(&l.Ref, &l.Payload); err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
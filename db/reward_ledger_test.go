@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+// ORACLE_TEST_DSN: 마이그레이션을 적용할 일회용 DB (없으면 건너뜀)
+func openTestDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+	dsn := os.Getenv("ORACLE_TEST_DSN")
+	if dsn == "" {
+		tb.Skip("ORACLE_TEST_DSN not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := MigrateUp(ctx, db, 0); err != nil {
+		tb.Fatal(err)
+	}
+	return db
+}
+
+// 라운드 1건 기록(잠금/streak/UPDATE/INSERT 고정 왕복) 지연: 참여자 200 / 2천 / 2만 (+ 미참여 5%)
+func BenchmarkRecordRewardRoundTx(b *testing.B) {
+	db := openTestDB(b)
+	const fullnode = "bench-reward-ledger"
+	b.Cleanup(func() {
+		ctx := context.Background()
+		_, _ = db.ExecContext(ctx, `
+DELETE FROM reward_credit WHERE round_id IN (SELECT round_id FROM reward_round WHERE fullnode_id = $1)`, fullnode)
+		_, _ = db.ExecContext(ctx, `DELETE FROM reward_round WHERE fullnode_id = $1`, fullnode)
+		_, _ = db.ExecContext(ctx, `DELETE FROM vote_counter WHERE address LIKE 'bench-%'`)
+	})
+
+	credit := func(st VoteCounterState) RewardCredit {
+		return RewardCredit{Increment: 1, Streak: st.Streak + 1, Multiplier: 1, Reward: 0.5,
+			AfterCount: st.Count + 1.5, AfterLastTime: time.Now()}
+	}
+	miss := func(st VoteCounterState) RewardCredit {
+		return RewardCredit{Multiplier: 1, AfterCount: st.Count, MissStreak: st.Missed + 1}
+	}
+	for _, n := range []int{200, 2_000, 20_000} {
+		addrs := make([]string, n)
+		for i := range addrs {
+			addrs[i] = fmt.Sprintf("bench-%d-%06d", n, i)
+		}
+		missed := make([]string, n/20)
+		for i := range missed {
+			missed[i] = fmt.Sprintf("bench-%d-miss-%06d", n, i)
+		}
+		b.Run(fmt.Sprintf("addrs=%d", n), func(b *testing.B) {
+			ctx := context.Background()
+			round := RewardRound{FullnodeID: fullnode, Participants: n, Total: n + len(missed), BaseReward: 0.5}
+			// 첫 라운드는 vote_counter 행 생성이 섞이므로 측정에서 제외
+			if _, _, err := RecordRewardRoundTx(ctx, db, round, addrs, missed, credit, miss); err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := RecordRewardRoundTx(ctx, db, round, addrs, missed, credit, miss); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*(n+len(missed))), "ns/addr")
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// oracle/db/reward_statement.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// 주소별 보상 내역 1행 (reward_credit + reward_round)
+type AccountRewardRow struct {
+	RoundID         int64      `json:"round_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	FullnodeID      string     `json:"fullnode_id"`
+	Kind            string     `json:"kind"`
+	Participants    int        `json:"n"`
+	Total           int        `json:"N"`
+	Rate            float64    `json:"rate"` // 라운드 참여율 n/N
+	BaseReward      float64    `json:"base_reward"`
+	Region          string     `json:"region,omitempty"` // 권역별 R0 적용 시 등록 권역
+	R0              float64    `json:"r0"`               // 이 주소에 적용한 R0
+	PolicyVersion   string     `json:"policy_version"`
+	Streak          int        `json:"streak"`
+	Multiplier      float64    `json:"multiplier"`
+	Reward          float64    `json:"reward"`        // miss는 차감(<= 0)
+	RunningTotal    float64    `json:"running_total"` // 첫 라운드부터 이 라운드까지 reward 합
+	Reset           bool       `json:"reset"`
+	BeforeCount     float64    `json:"before_count"`
+	AfterCount      float64    `json:"after_count"`
+	MissStreak      int        `json:"miss_streak"`
+	IneligibleUntil *time.Time `json:"ineligible_until,omitempty"`
+	ContentHash     string     `json:"content_hash"`
+}
+
+// 주소별 보상 요약 + 현재 vote_counter 점수
+type AccountRewardSummary struct {
+	Address         string     `json:"address"`
+	Score           float64    `json:"score"` // vote_counter.count (선발 가중치에 쓰이는 현재 값)
+	LastTime        *time.Time `json:"last_time,omitempty"`
+	Rounds          int        `json:"rounds"` // 참여(credit) 라운드 수
+	Missed          int        `json:"missed"`
+	Earned          float64    `json:"earned"`   // 지급 합계
+	Deducted        float64    `json:"deducted"` // 미참여 차감 합계 (양수)
+	Net             float64    `json:"net"`      // earned - deducted
+	AvgRate         float64    `json:"avg_rate"` // 참여 라운드 평균 참여율
+	FirstRoundAt    *time.Time `json:"first_round_at,omitempty"`
+	LastRoundAt     *time.Time `json:"last_round_at,omitempty"`
+	IneligibleUntil *time.Time `json:"ineligible_until,omitempty"` // 미참여 패널티로 선발 제외 중이면 종료 시각
+}
+
+const accountRewardSelect = `
+SELECT round_id, created_at, fullnode_id, kind, n_participants, n_total, participation, base_reward, region, r0,
+       policy_version, streak, multiplier, reward, running_total, reset, before_count, after_count, miss_streak,
+       ineligible_until, content_hash
+  FROM (SELECT c.round_id, r.created_at, r.fullnode_id, c.kind, r.n_participants, r.n_total, r.participation, r.base_reward,
+               c.region, COALESCE(c.r0, r.r0) AS r0, r.policy_version, c.streak, c.multiplier, c.reward,
+               SUM(c.reward) OVER (ORDER BY c.round_id) AS running_total,
+               c.reset, c.before_count, c.after_count, c.miss_streak, c.ineligible_until, c.content_hash
+          FROM reward_credit c
+          JOIN reward_round r ON r.round_id = c.round_id
+         WHERE c.address = $1) s`
+
+func scanAccountReward(rows *sql.Rows) (AccountRewardRow, error) {
+	var x AccountRewardRow
+	var until sql.NullTime
+	err := rows.Scan(&x.RoundID, &x.CreatedAt, &x.FullnodeID, &x.Kind, &x.Participants, &x.Total, &x.Rate,
+		&x.BaseReward, &x.Region, &x.R0, &x.PolicyVersion, &x.Streak, &x.Multiplier, &x.Reward, &x.RunningTotal, &x.Reset,
+		&x.BeforeCount, &x.AfterCount, &x.MissStreak, &until, &x.ContentHash)
+	if until.Valid {
+		t := until.Time
+		x.IneligibleUntil = &t
+	}
+	return x, err
+}
+
+// 최신 라운드부터 limit건 (beforeRound > 0이면 그 라운드 미만만: 다음 페이지 커서)
+func ListAccountRewards(ctx context.Context, db *sql.DB, address string, beforeRound int64, limit int) ([]AccountRewardRow, error) {
+	rows, err := db.QueryContext(ctx, accountRewardSelect+`
+ WHERE $2 <= 0 OR round_id < $2
+ ORDER BY round_id DESC
+ LIMIT $3`, address, beforeRound, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []AccountRewardRow{}
+	for rows.Next() {
+		x, err := scanAccountReward(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, x)
+	}
+	return out, rows.Err()
+}
+
+// 전체 내역을 오래된 순으로 순회 (CSV 내보내기용, 메모리에 모으지 않음)
+func ForEachAccountReward(ctx context.Context, db *sql.DB, address string, fn func(AccountRewardRow) error) error {
+	rows, err := db.QueryContext(ctx, accountRewardSelect+`
+ ORDER BY round_id`, address)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		x, err := scanAccountReward(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(x); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// 주소의 누적 보상 요약 (원장 기준) + 현재 점수
+func GetAccountRewardSummary(ctx context.Context, db *sql.DB, address string) (AccountRewardSummary, error) {
+	s := AccountRewardSummary{Address: address}
+	var first, last, until sql.NullTime
+	err := db.QueryRowContext(ctx, `
+SELECT COUNT(*) FILTER (WHERE c.kind = 'credit'),
+       COUNT(*) FILTER (WHERE c.kind = 'miss'),
+       COALESCE(SUM(c.reward) FILTER (WHERE c.reward > 0), 0),
+       COALESCE(-SUM(c.reward) FILTER (WHERE c.reward < 0), 0),
+       COALESCE(AVG(r.participation) FILTER (WHERE c.kind = 'credit'), 0),
+       MIN(r.created_at), MAX(r.created_at),
+       MAX(c.ineligible_until) FILTER (WHERE c.ineligible_until > now())
+  FROM reward_credit c
+  JOIN reward_round r ON r.round_id = c.round_id
+ WHERE c.address = $1`, address).Scan(&s.Rounds, &s.Missed, &s.Earned, &s.Deducted, &s.AvgRate, &first, &last, &until)
+	if err != nil {
+		return s, err
+	}
+	s.Net = s.Earned - s.Deducted
+	for _, p := range []struct {
+		src sql.NullTime
+		dst **time.Time
+	}{{first, &s.FirstRoundAt}, {last, &s.LastRoundAt}, {until, &s.IneligibleUntil}} {
+		if p.src.Valid {
+			t := p.src.Time
+			*p.dst = &t
+		}
+	}
+
+	var lastTime sql.NullTime
+	err = db.QueryRowContext(ctx, `SELECT count, last_time FROM vote_counter WHERE address = $1`, address).
+		Scan(&s.Score, &lastTime)
+	if err != nil && err != sql.ErrNoRows {
+		return s, err
+	}
+	if lastTime.Valid {
+		t := lastTime.Time
+		s.LastTime = &t
+	}
+	return s, nil
+}
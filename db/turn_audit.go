@@ -0,0 +1,329 @@
+// oracle/db/turn_audit.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// 턴별 후보 감사 기록 1행
+// - 적격 후보: 가중치/확률 산출 과정 전부
+// - 제외 후보: eligible=false + 제외 사유(reason)
+type TurnAuditRow struct {
+	Address   string  `json:"address"`
+	EntityID  string  `json:"entity_id"`
+	Eligible  bool    `json:"eligible"`
+	Reason    string  `json:"reason,omitempty"`
+	EnergyKwh float64 `json:"energy_kwh"`       // 입력 에너지(변환 전)
+	VoteScore float64 `json:"vote_score"`       // vote_counter.count
+	X         float64 `json:"x_i"`              // x_i
+	R         float64 `json:"r_i"`              // r_i
+	WBase     float64 `json:"w_base"`           // 패널티 전 w_i
+	Penalty   float64 `json:"penalty"`          // 공정성 패널티 계수 (1 = 없음)
+	PenaltyR  int     `json:"penalty_r"`        // 남은 패널티 턴수 R
+	W         float64 `json:"w_i"`              // 패널티 후 w_i
+	PPreCap   float64 `json:"p_pre_cap"`        // P-cap 전 P_i
+	P         float64 `json:"p_i"`              // 최종 P_i
+	Region    string  `json:"region,omitempty"` // 등록 위치 기반 권역
+	GeoFactor float64 `json:"geo_factor"`       // 권역 다양성 보정 계수 (1 = 없음)
+	PFloor    float64 `json:"p_floor"`          // 확률 하한 보정량 (P-cap 전)
+}
+
+// 계정 적격성 판단에 필요한 사실
+type AccountFacts struct {
+	Registered bool
+	CreatedAt  time.Time // 같은 주소의 가장 이른 등록 시각
+	Denylisted bool
+	DenyReason string
+	// 연속 미참여 패널티로 선발 후보에서 제외되는 시각까지 (zero = 없음)
+	PenalizedUntil time.Time
+}
+
+// 후보 주소들의 등록 여부 / 최초 등록 시각 / 차단 여부 / 미참여 패널티 조회
+// - missPenalty=false면 reward_credit(ineligible_until) 조회를 건너뛴다 (PenalizedUntil zero)
+func FetchAccountFacts(ctx context.Context, db *sql.DB, addrs []string, missPenalty bool) (map[string]AccountFacts, error) {
+	out := make(map[string]AccountFacts, len(addrs))
+	if len(addrs) == 0 {
+		return out, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `
+SELECT address, MIN(created_at)
+  FROM userData
+ WHERE address = ANY($1)
+ GROUP BY address`, pq.Array(addrs))
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var a string
+		var created sql.NullTime
+		if err := rows.Scan(&a, &created); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		f := out[a]
+		f.Registered = true
+		if created.Valid {
+			f.CreatedAt = created.Time
+		}
+		out[a] = f
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = db.QueryContext(ctx, `
+SELECT address, reason
+  FROM selection_denylist
+ WHERE address = ANY($1)
+   AND (expires_at IS NULL OR expires_at > now())`, pq.Array(addrs))
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var a, reason string
+		if err := rows.Scan(&a, &reason); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		f := out[a]
+		f.Denylisted = true
+		f.DenyReason = reason
+		out[a] = f
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if !missPenalty {
+		return out, nil
+	}
+	rows, err = db.QueryContext(ctx, `
+SELECT address, MAX(ineligible_until)
+  FROM reward_credit
+ WHERE address = ANY($1)
+   AND ineligible_until > now()
+ GROUP BY address`, pq.Array(addrs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a string
+		var until time.Time
+		if err := rows.Scan(&a, &until); err != nil {
+			return nil, err
+		}
+		f := out[a]
+		f.PenalizedUntil = until
+		out[a] = f
+	}
+	return out, rows.Err()
+}
+
+// turn_result 1행
+type TurnRecord struct {
+	TurnID     int64
+	FullnodeID string
+	Creator    string
+	Weight     float64
+	InputHash  string // 기여자 입력 해시 (재요청 충돌 감지)
+	SnapshotID string // 점수를 읽은 DB 스냅샷 (재현용)
+	Params     []byte // 선발 파라미터 JSON
+}
+
+// 턴 결과 + 후보 감사 기록을 한 트랜잭션으로 저장 (turn_id 멱등)
+// - turn_id advisory lock 하에서 기존 결과를 먼저 확인하고, 있으면 저장하지 않고 그 결과를 돌려준다 (claimed=false)
+// - 호출자는 커밋이 끝난 뒤 돌려받은 결과(stored)만 송신한다 → 동시 배달/재시도에도 턴당 당첨자 1명
+// - READ COMMITTED: 락을 잡은 뒤의 문장이 먼저 커밋된 결과를 볼 수 있어야 한다
+// - 같은 turn_id가 legacy_offset 행이면 오류 (offset 키였을 수 있어 이 height의 결과로 쓸 수 없다)
+func FinalizeTurnWithAuditTx(ctx context.Context, db *sql.DB, rec TurnRecord, audit []TurnAuditRow) (stored TurnResult, claimed bool, err error) {
+	turnID := rec.TurnID
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return stored, false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, turnID); err != nil {
+		return stored, false, err
+	}
+	var legacy bool
+	err = tx.QueryRowContext(ctx, `
+SELECT turn_id, fullnode_id, creator, weight, input_hash, legacy_offset
+  FROM turn_result
+ WHERE turn_id = $1`, turnID).Scan(&stored.TurnID, &stored.FullnodeID, &stored.Creator, &stored.Weight, &stored.InputHash, &legacy)
+	if err == nil && legacy {
+		// offset 키였을 수 있는 과거 행: 이 height의 결과로 재송신하지도, 새로 결정하지도 않는다
+		return TurnResult{}, false, fmt.Errorf("turn_id %d collides with a legacy offset-keyed turn_result row (migration 026); clean up before deciding this height", turnID)
+	}
+	if err == nil {
+		return stored, false, nil // 이미 결정된 턴: 감사 기록도 그대로 둔다
+	}
+	if err != sql.ErrNoRows {
+		return stored, false, err
+	}
+	err = nil
+
+	// 해시 체인 연결
+	seq, prevHash, err := nextTurnChainLinkTx(ctx, tx)
+	if err != nil {
+		return stored, false, err
+	}
+	contentHash := turnContentHash(seq, strconv.FormatInt(turnID, 10), rec.FullnodeID, rec.Creator, rec.Weight,
+		rec.InputHash, rec.SnapshotID, rec.Params, prevHash)
+
+	if _, err = tx.ExecContext(ctx, `
+        INSERT INTO turn_result (turn_id, fullnode_id, creator, weight, input_hash, snapshot_id, params,
+                                 chain_seq, content_hash, prev_hash)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `, turnID, rec.FullnodeID, rec.Creator, rec.Weight, rec.InputHash, rec.SnapshotID, rec.Params,
+		seq, contentHash, prevHash); err != nil {
+		return stored, false, err
+	}
+	stored = TurnResult{TurnID: turnID, FullnodeID: rec.FullnodeID, Creator: rec.Creator, Weight: rec.Weight, InputHash: rec.InputHash}
+	if len(audit) == 0 {
+		return stored, true, nil
+	}
+
+	n := len(audit)
+	var (
+		addr, entity, reason = make([]string, n), make([]string, n), make([]string, n)
+		eligible             = make([]bool, n)
+		penaltyR             = make([]int64, n)
+		energy, score        = make([]float64, n), make([]float64, n)
+		x, r, wBase, penalty = make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+		w, pPre, p           = make([]float64, n), make([]float64, n), make([]float64, n)
+		region               = make([]string, n)
+		geo, floor           = make([]float64, n), make([]float64, n)
+	)
+	for i, a := range audit {
+		addr[i], entity[i], reason[i] = a.Address, a.EntityID, a.Reason
+		eligible[i], penaltyR[i] = a.Eligible, int64(a.PenaltyR)
+		energy[i], score[i] = a.EnergyKwh, a.VoteScore
+		x[i], r[i], wBase[i], penalty[i] = a.X, a.R, a.WBase, a.Penalty
+		w[i], pPre[i], p[i] = a.W, a.PPreCap, a.P
+		region[i], geo[i], floor[i] = a.Region, a.GeoFactor, a.PFloor
+	}
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO turn_audit
+(turn_id, address, entity_id, eligible, reason, energy_kwh, vote_score,
+ x_i, r_i, w_base, penalty, penalty_r, w_i, p_pre_cap, p_i, region, geo_factor, p_floor)
+SELECT $1, u.*
+  FROM unnest($2::text[], $3::text[], $4::bool[], $5::text[], $6::float8[], $7::float8[],
+              $8::float8[], $9::float8[], $10::float8[], $11::float8[], $12::int[],
+              $13::float8[], $14::float8[], $15::float8[], $16::text[], $17::float8[],
+              $18::float8[]) AS u
+ON CONFLICT (turn_id, address) DO NOTHING`,
+		turnID, pq.Array(addr), pq.Array(entity), pq.Array(eligible), pq.Array(reason),
+		pq.Array(energy), pq.Array(score), pq.Array(x), pq.Array(r), pq.Array(wBase),
+		pq.Array(penalty), pq.Array(penaltyR), pq.Array(w), pq.Array(pPre), pq.Array(p),
+		pq.Array(region), pq.Array(geo), pq.Array(floor))
+	if err != nil {
+		return stored, false, err
+	}
+	return stored, true, nil
+}
+
+// 턴의 후보 감사 기록 전체 (주소 정렬)
+func GetTurnAudit(ctx context.Context, db *sql.DB, turnID int64) ([]TurnAuditRow, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT address, entity_id, eligible, reason, energy_kwh, vote_score,
+       x_i, r_i, w_base, penalty, penalty_r, w_i, p_pre_cap, p_i, region, geo_factor, p_floor
+  FROM turn_audit
+ WHERE turn_id = $1
+ ORDER BY address`, turnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]TurnAuditRow, 0)
+	for rows.Next() {
+		var a TurnAuditRow
+		if err := rows.Scan(&a.Address, &a.EntityID, &a.Eligible, &a.Reason, &a.EnergyKwh, &a.VoteScore,
+			&a.X, &a.R, &a.WBase, &a.Penalty, &a.PenaltyR, &a.W, &a.PPreCap, &a.P, &a.Region, &a.GeoFactor, &a.PFloor); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// 턴 결과의 당첨자 + 저장된 선발 파라미터 JSON (params가 없던 과거 턴은 nil)
+func GetTurnParams(ctx context.Context, db *sql.DB, turnID int64) (creator string, params []byte, found bool, err error) {
+	err = db.QueryRowContext(ctx, `
+SELECT creator, params FROM turn_result WHERE turn_id = $1`, turnID).Scan(&creator, &params)
+	if err == sql.ErrNoRows {
+		return "", nil, false, nil
+	}
+	if err != nil {
+		return "", nil, false, err
+	}
+	return creator, params, true, nil
+}
+
+// 선발 차단 목록 항목
+type DenylistEntry struct {
+	Address   string     `json:"address"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func UpsertDenylist(ctx context.Context, db *sql.DB, e DenylistEntry) error {
+	_, err := db.ExecContext(ctx, `
+INSERT INTO selection_denylist (address, reason, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (address) DO UPDATE
+SET reason = EXCLUDED.reason, expires_at = EXCLUDED.expires_at`,
+		e.Address, e.Reason, e.ExpiresAt)
+	return err
+}
+
+func DeleteDenylist(ctx context.Context, db *sql.DB, address string) error {
+	_, err := db.ExecContext(ctx, `DELETE FROM selection_denylist WHERE address = $1`, address)
+	return err
+}
+
+// 만료되지 않은 차단 항목 전체
+func ListDenylist(ctx context.Context, db *sql.DB) ([]DenylistEntry, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT address, reason, expires_at
+  FROM selection_denylist
+ WHERE expires_at IS NULL OR expires_at > now()
+ ORDER BY address`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]DenylistEntry, 0)
+	for rows.Next() {
+		var e DenylistEntry
+		var exp sql.NullTime
+		if err := rows.Scan(&e.Address, &e.Reason, &exp); err != nil {
+			return nil, err
+		}
+		if exp.Valid {
+			t := exp.Time
+			e.ExpiresAt = &t
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
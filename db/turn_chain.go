@@ -0,0 +1,170 @@
+// oracle/db/turn_chain.go
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// turn_result 해시 체인
+// - chain_seq    : 체인 순번 (1부터, 빈 번호 = 삭제된 행)
+// - content_hash : sha256(정규화 내용 + prev_hash)
+// - prev_hash    : 직전 순번 행의 content_hash (첫 행은 빈 문자열)
+// 체인 도입 이전 행은 chain_seq가 NULL이며 검증 대상이 아니다.
+
+// 체인 헤드 (가장 큰 chain_seq)
+type TurnChainHead struct {
+	Seq         int64  `json:"seq"`
+	TurnID      string `json:"turn_id"`
+	ContentHash string `json:"content_hash"`
+}
+
+// 검증 중 발견한 끊김
+type TurnChainBreak struct {
+	Seq    int64  `json:"seq"`
+	TurnID string `json:"turn_id"`
+	Kind   string `json:"kind"` // "gap" | "prev_mismatch" | "content_mismatch" | "unchained"
+	Detail string `json:"detail"`
+}
+
+type TurnChainReport struct {
+	Checked int              `json:"checked"`
+	Head    TurnChainHead    `json:"head"`
+	Breaks  []TurnChainBreak `json:"breaks"`
+}
+
+// JSON을 키 정렬/공백 제거 형태로 (JSONB 저장 후에도 같은 바이트가 되도록)
+func canonicalJSON(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return string(b)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return string(b)
+	}
+	return string(out)
+}
+
+func turnContentHash(seq int64, turnID, fullnodeID, creator string, weight float64,
+	inputHash, snapshotID string, params []byte, prevHash string) string {
+	s := fmt.Sprintf("v1\nseq=%d\nturn_id=%s\nfullnode_id=%s\ncreator=%s\nweight=%s\ninput_hash=%s\nsnapshot_id=%s\nparams=%s\nprev=%s\n",
+		seq, turnID, fullnodeID, creator, strconv.FormatFloat(weight, 'g', -1, 64),
+		inputHash, snapshotID, canonicalJSON(params), prevHash)
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// 체인 끝에 붙일 순번/이전 해시 (같은 tx 안에서 체인 락을 잡은 뒤 호출)
+func nextTurnChainLinkTx(ctx context.Context, tx *sql.Tx) (seq int64, prev string, err error) {
+	if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('turn_result_chain'))`); err != nil {
+		return 0, "", err
+	}
+	err = tx.QueryRowContext(ctx, `
+SELECT chain_seq, content_hash FROM turn_result
+ WHERE chain_seq IS NOT NULL
+ ORDER BY chain_seq DESC LIMIT 1`).Scan(&seq, &prev)
+	if err == sql.ErrNoRows {
+		return 1, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	return seq + 1, prev, nil
+}
+
+func GetTurnChainHead(ctx context.Context, db *sql.DB) (TurnChainHead, bool, error) {
+	var h TurnChainHead
+	err := db.QueryRowContext(ctx, `
+SELECT chain_seq, turn_id, content_hash FROM turn_result
+ WHERE chain_seq IS NOT NULL
+ ORDER BY chain_seq DESC LIMIT 1`).Scan(&h.Seq, &h.TurnID, &h.ContentHash)
+	if err == sql.ErrNoRows {
+		return h, false, nil
+	}
+	if err != nil {
+		return h, false, err
+	}
+	return h, true, nil
+}
+
+// VerifyTurnChain
+// - chain_seq 순서로 전체를 걸으며 내용 해시 재계산, prev 연결, 순번 빈칸을 검사
+// - 체인 시작 이후에 chain_seq 없이 들어온 행도 보고
+func VerifyTurnChain(ctx context.Context, db *sql.DB) (TurnChainReport, error) {
+	rep := TurnChainReport{Breaks: []TurnChainBreak{}}
+	rows, err := db.QueryContext(ctx, `
+SELECT chain_seq, turn_id, fullnode_id, creator, weight, input_hash, snapshot_id,
+       COALESCE(params::text, ''), content_hash, prev_hash
+  FROM turn_result
+ WHERE chain_seq IS NOT NULL
+ ORDER BY chain_seq`)
+	if err != nil {
+		return rep, err
+	}
+	defer rows.Close()
+
+	var (
+		expectSeq int64 = 1
+		prevHash        = ""
+	)
+	for rows.Next() {
+		var (
+			seq                                  int64
+			turnID, fid, creator, inHash, snapID string
+			params, contentHash, storedPrev      string
+			weight                               float64
+		)
+		if err := rows.Scan(&seq, &turnID, &fid, &creator, &weight, &inHash, &snapID,
+			&params, &contentHash, &storedPrev); err != nil {
+			return rep, err
+		}
+		rep.Checked++
+		if seq != expectSeq {
+			rep.Breaks = append(rep.Breaks, TurnChainBreak{Seq: seq, TurnID: turnID, Kind: "gap",
+				Detail: fmt.Sprintf("expected seq %d (rows %d..%d missing)", expectSeq, expectSeq, seq-1)})
+		}
+		if storedPrev != prevHash {
+			rep.Breaks = append(rep.Breaks, TurnChainBreak{Seq: seq, TurnID: turnID, Kind: "prev_mismatch",
+				Detail: fmt.Sprintf("prev_hash=%s, previous row content_hash=%s", storedPrev, prevHash)})
+		}
+		if want := turnContentHash(seq, turnID, fid, creator, weight, inHash, snapID, []byte(params), storedPrev); want != contentHash {
+			rep.Breaks = append(rep.Breaks, TurnChainBreak{Seq: seq, TurnID: turnID, Kind: "content_mismatch",
+				Detail: fmt.Sprintf("stored=%s recomputed=%s", contentHash, want)})
+		}
+		prevHash = contentHash
+		expectSeq = seq + 1
+		rep.Head = TurnChainHead{Seq: seq, TurnID: turnID, ContentHash: contentHash}
+	}
+	if err := rows.Err(); err != nil {
+		return rep, err
+	}
+
+	// 체인 시작 후 체인 밖으로 기록된 행
+	urows, err := db.QueryContext(ctx, `
+SELECT turn_id FROM turn_result
+ WHERE chain_seq IS NULL
+   AND created_at >= (SELECT MIN(created_at) FROM turn_result WHERE chain_seq IS NOT NULL)
+ ORDER BY created_at`)
+	if err != nil {
+		return rep, err
+	}
+	defer urows.Close()
+	for urows.Next() {
+		var t string
+		if err := urows.Scan(&t); err != nil {
+			return rep, err
+		}
+		rep.Breaks = append(rep.Breaks, TurnChainBreak{TurnID: t, Kind: "unchained",
+			Detail: "row written after chain start without chain_seq"})
+	}
+	return rep, urows.Err()
+}
@@ -0,0 +1,67 @@
+// oracle/db/user_region.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+func UpsertUserLocation(ctx context.Context, db *sql.DB, address, location string) error {
+	if address == "" {
+		return fmt.Errorf("UpsertUserLocation: empty address")
+	}
+	_, err := db.ExecContext(ctx, `
+INSERT INTO user_region (address, location, updated_at)
+VALUES ($1, $2, now())
+ON CONFLICT (address) DO UPDATE
+SET location = EXCLUDED.location, updated_at = now()`, address, location)
+	return err
+}
+
+// 여러 address의 등록 위치 조회 (없으면 맵에 없음)
+func GetUserLocations(ctx context.Context, db *sql.DB, addrs []string) (map[string]string, error) {
+	out := make(map[string]string, len(addrs))
+	if len(addrs) == 0 {
+		return out, nil
+	}
+	rows, err := db.QueryContext(ctx,
+		`SELECT address, location FROM user_region WHERE address = ANY($1)`, pq.Array(addrs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a, loc string
+		if err := rows.Scan(&a, &loc); err != nil {
+			return nil, err
+		}
+		out[a] = loc
+	}
+	return out, rows.Err()
+}
+
+// 최근 N턴 승자의 등록 위치 (위치 미등록 승자는 빈 문자열)
+func FetchRecentWinnerLocations(ctx context.Context, db *sql.DB, currentTurn int64, N int) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT COALESCE(u.location, '')
+  FROM turn_result t
+  LEFT JOIN user_region u ON u.address = t.creator
+ WHERE t.turn_id > $1 - $2
+   AND NOT t.legacy_offset`, currentTurn, N)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]string, 0, N)
+	for rows.Next() {
+		var loc string
+		if err := rows.Scan(&loc); err != nil {
+			return nil, err
+		}
+		out = append(out, loc)
+	}
+	return out, rows.Err()
+}
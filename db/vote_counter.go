@@ -8,25 +8,6 @@ import (
 	"strings"
 )
 
-// address에 delta 점수를 누적(UPSERT) + last_time = NOW()
-func UpsertVoteCounter(ctx context.Context, db *sql.DB, address string, delta float64) error {
-	if address == "" {
-		return fmt.Errorf("UpsertVoteCounter: empty address")
-	}
-	if delta == 0 {
-		return nil
-	}
-	const q = `
-INSERT INTO vote_counter (address, last_time, count)
-VALUES ($1, NOW(), $2)
-ON CONFLICT (address) DO UPDATE
-SET last_time = NOW(),
-    count = vote_counter.count + EXCLUDED.count;
-`
-	_, err := db.ExecContext(ctx, q, address, delta)
-	return err
-}
-
 // 여러 address의 누적 점수 조회 (없으면 맵에 없음)
 func GetVoteCountsByAddresses(ctx context.Context, db *sql.DB, addresses []string) (map[string]float64, error) {
 	out := make(map[string]float64, len(addresses))
@@ -62,3 +43,27 @@ func GetVoteCountsByAddresses(ctx context.Context, db *sql.DB, addresses []strin
 	}
 	return out, rows.Err()
 }
+
+// vote_counter 전체 (address -> count). 인메모리 인덱스 재동기화용
+func LoadAllVoteCounts(ctx context.Context, db *sql.DB) (map[string]float64, error) {
+	rows, err := db.QueryContext(ctx, `SELECT address, count FROM vote_counter`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string]float64, 4096)
+	for rows.Next() {
+		var a string
+		var c float64
+		if err := rows.Scan(&a, &c); err != nil {
+			return nil, err
+		}
+		if a != "" {
+			out[a] = c
+		}
+	}
+	return out, rows.Err()
+}
+
+// vote_counter 변경 시 NOTIFY (다른 writer의 변경을 인메모리 인덱스에 반영)
+const VoteCounterNotifyChannel = "vote_counter_changed"
@@ -0,0 +1,80 @@
+// oracle/db/vote_snapshot.go
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// 선발 입력(vote-only 후보 + 후보 점수)을 한 시점에서 읽은 결과
+type VoteSnapshot struct {
+	SnapshotID string              // txid_current_snapshot() (xmin:xmax:xip_list)
+	VoteOnly   map[string]struct{} // count > 0 주소
+	Scores     map[string]float64  // vote-only ∪ 기여자 주소의 누적 점수 (없으면 맵에 없음)
+}
+
+// ReadVoteSnapshot
+// - REPEATABLE READ / READ ONLY 트랜잭션 하나에서 vote-only 목록과 점수를 함께 읽는다
+// - 보상 컨슈머가 동시에 vote_counter를 갱신해도 후보 집합과 점수가 같은 시점 값이 된다
+func ReadVoteSnapshot(ctx context.Context, db *sql.DB, contribAddrs []string) (snap VoteSnapshot, err error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return snap, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// 첫 쿼리에서 스냅샷이 고정된다
+	if err = tx.QueryRowContext(ctx, `SELECT txid_current_snapshot()::text`).Scan(&snap.SnapshotID); err != nil {
+		return snap, err
+	}
+
+	snap.VoteOnly = make(map[string]struct{}, 1024)
+	snap.Scores = make(map[string]float64, 1024+len(contribAddrs))
+
+	rows, err := tx.QueryContext(ctx, `SELECT address, count FROM vote_counter WHERE count > 0`)
+	if err != nil {
+		return snap, err
+	}
+	for rows.Next() {
+		var a string
+		var c float64
+		if err = rows.Scan(&a, &c); err != nil {
+			rows.Close()
+			return snap, err
+		}
+		if a == "" {
+			continue
+		}
+		snap.VoteOnly[a] = struct{}{}
+		snap.Scores[a] = c
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return snap, err
+	}
+
+	// 기여자 중 count <= 0 인 주소의 점수 (0 또는 음수)
+	if len(contribAddrs) > 0 {
+		rows, err = tx.QueryContext(ctx,
+			`SELECT address, count FROM vote_counter WHERE address = ANY($1) AND count <= 0`, pq.Array(contribAddrs))
+		if err != nil {
+			return snap, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var a string
+			var c float64
+			if err = rows.Scan(&a, &c); err != nil {
+				return snap, err
+			}
+			snap.Scores[a] = c
+		}
+		if err = rows.Err(); err != nil {
+			return snap, err
+		}
+	}
+	return snap, tx.Commit()
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
 	"oracle/config"
 	api "oracle/connect"
 	"oracle/consumer"
 	"oracle/db"
 	"oracle/producer"
+	"os"
+	"time"
 
 	"net/http"
 )
@@ -19,8 +22,18 @@ func enableCORS(w http.ResponseWriter) {
 
 func main() {
 	database := db.ConnectDB()
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(database, os.Args[1:]))
+	}
+	// 스키마 버전 확인: 마이그레이션은 `oracle migrate up`으로만 적용
+	ctxSchema, cancelSchema := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := db.CheckSchemaVersion(ctxSchema, database); err != nil {
+		log.Fatalf("[Schema] unexpected schema version: %v", err)
+	}
+	cancelSchema()
 	accountCreateWriter := producer.NewAccounCreatetWriter()
 	txHashWriter := producer.NewTxHashWriter()
+	rewardWriter := producer.InitRewardProducer()
 
 	writer, err := producer.NewSaramaProducer(config.KafkaBrokers)
 	if err != nil {
@@ -50,14 +63,41 @@ func main() {
 		api.VerifyHandler(database)(w, r) // VerifyHandler는 connect/verify.go에 구현
 	})
 
+	// 관리자 API: 엔티티(Sybil) 클러스터 조회 / 수동 연결
+	http.HandleFunc("/admin/entities", api.RequireAdmin(api.EntityClustersHandler(database)))
+	// 관리자 API: 디바이스 소유자 레지스트리 (엔티티 그룹핑 키)
+	http.HandleFunc("/admin/device-owners", api.RequireAdmin(api.DeviceOwnerHandler(database)))
+	// 관리자 API: 발전소 설비 용량 레지스트리 (x_i capacity 정규화)
+	http.HandleFunc("/admin/plants", api.RequireAdmin(api.PlantCapacityHandler(database)))
+	// 관리자 API: 선발 차단 목록 (적격성 규칙)
+	http.HandleFunc("/admin/denylist", api.RequireAdmin(api.DenylistHandler(database)))
+	// 관리자 API: 기여자 보고 quorum에 참여하는 등록 풀노드
+	http.HandleFunc("/admin/fullnodes", api.RequireAdmin(api.FullnodeRegistryHandler(database)))
+	// 운영자 API(읽기 전용 토큰): 파라미터 덮어쓰기 가상 선발 (what-if, 기록 없음)
+	http.HandleFunc("/admin/selection/what-if", api.RequireOperator(api.WhatIfHandler(database)))
+	// 운영자 API(읽기 전용 토큰): 턴/주소별 선발 확률 분해
+	http.HandleFunc("/admin/selection/explain", api.RequireOperator(api.ExplainHandler(database)))
+	// 턴/보상 Merkle 포함 증명 (온체인 앵커 검증용, 공개)
+	http.HandleFunc("/anchors/proof", api.AnchorProofHandler(database))
+	// 파라미터 거버넌스 제안/투표 이력 (공개)
+	http.HandleFunc("/governance/proposals", api.GovernanceHistoryHandler(database))
+	// 현재 epoch 보상 발행 예산/잔여 (공개)
+	http.HandleFunc("/rewards/emission", api.EmissionHandler(database))
+	// 주소별 보상 내역/요약 + CSV 내보내기 (공개)
+	http.HandleFunc("/accounts/{address}/rewards", api.AccountRewardsHandler(database))
+
 	go consumer.StartMappingConsumer(database, writer)           // device Id -> address
 	go producer.StartRequestVoteMemberConsumer(database, writer) // 유권자 수 전송
 	//go consumer.StartLocationConsumer(database)  // 위치정보 요청
-	go consumer.StartVMemberRewardConsumer(database) // 서명자 보상
-	go consumer.StartTxHashConsumer(database)        // tx hash값 저장
+	go consumer.StartVMemberRewardConsumer(database, rewardWriter) // 서명자 보상 (결과는 풀노드로 송신)
+	go consumer.StartTxHashConsumer(database)                      // tx hash값 저장
 	go consumer.StartRequestTxHashConsumer(database, txHashWriter)
 	// go producer.StartOracleProducer(writer)
+	go consumer.StartGovernanceConsumer(database) // 풀노드 파라미터 거버넌스
 	go consumer.StartBlockCreatorConsumer(database, writer)
+	if config.AnchorOn {
+		go consumer.StartAnchorJob(database) // 턴/보상 Merkle root 온체인 앵커링
+	}
 	log.Println("Server running on :3001")
 	log.Fatal(http.ListenAndServe(":3001", nil))
 	select {}
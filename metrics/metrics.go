@@ -21,15 +21,68 @@ var (
 	FairPcapAppliedGauge = prometheus.NewGauge(
 		prometheus.GaugeOpts{Name: "fair_pcap_applied", Help: "1 if P-cap triggered in the turn; otherwise 0"},
 	)
-	// 승자 카운터 (라벨: creator)
-	WinnerCounter = prometheus.NewCounterVec(
-		prometheus.CounterOpts{Name: "block_winner_total", Help: "Total wins per creator"},
-		[]string{"creator"},
+	// 같은 턴 재요청 (저장된 결과 재송신)
+	TurnReplayCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{Name: "turn_replay_total", Help: "Requests for an already-decided turn answered from the stored result"},
+	)
+	// 같은 턴 재요청인데 기여자 목록이 저장된 입력과 다름
+	TurnConflictCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{Name: "turn_conflict_total", Help: "Repeated turn requests whose contributor input differs from the stored one"},
+	)
+	// 턴별 확률 분포 (P-cap 적용 후 P_i)
+	SelectionEntropyGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "selection_p_entropy", Help: "Shannon entropy of P_i in the turn (nats)"},
+	)
+	SelectionEntropyNormGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "selection_p_entropy_normalized", Help: "Entropy of P_i divided by ln(candidates); 1 = uniform"},
+	)
+	SelectionGiniGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "selection_p_gini", Help: "Gini coefficient of P_i in the turn"},
+	)
+	SelectionHHIGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "selection_p_hhi", Help: "Herfindahl-Hirschman index (sum of P_i^2) in the turn"},
+	)
+	SelectionTop1Gauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "selection_p_top1", Help: "Largest P_i in the turn"},
+	)
+	SelectionGiniHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{Name: "selection_p_gini_distribution", Help: "Per-turn Gini coefficient of P_i",
+			Buckets: prometheus.LinearBuckets(0, 0.1, 11)},
+	)
+	SelectionWinnerPHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{Name: "selection_winner_probability", Help: "P_i of the selected creator",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.02, 0.05, 0.1, 0.2, 0.35, 0.5, 1}},
+	)
+	// 공정성 창(최근 N턴) 승리 집중도
+	WinConcentrationHHIGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "selection_win_hhi_window", Help: "HHI of creator win shares over the fairness window"},
+	)
+	WinTopShareGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "selection_win_top_share_window", Help: "Largest single creator win share over the fairness window"},
+	)
+	// 승자 순위 카운터 (라벨: P 기준 순위 구간 — 카디널리티 고정)
+	WinnerRankCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "block_winner_rank_total", Help: "Wins by the winner's P_i rank bucket (1,2,3,4-5,6-10,11+)"},
+		[]string{"rank"},
+	)
+	// 보상 발행 예산 (현재 epoch)
+	RewardEmissionBudgetGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "reward_emission_epoch_budget", Help: "Reward budget of the current emission epoch"},
+	)
+	RewardEmissionRemainingGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "reward_emission_remaining", Help: "Unspent reward budget of the current emission epoch"},
+	)
+	RewardEmissionScaledCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{Name: "reward_emission_scaled_rounds_total", Help: "Reward rounds scaled down pro-rata to fit the epoch budget"},
 	)
 )
 
 func InitAndServe(addr string) error {
-	prometheus.MustRegister(FairPenalizedGauge, FairMaxPenaltyGauge, FairCandidatesGauge, FairPcapAppliedGauge, WinnerCounter)
+	prometheus.MustRegister(FairPenalizedGauge, FairMaxPenaltyGauge, FairCandidatesGauge, FairPcapAppliedGauge,
+		TurnReplayCounter, TurnConflictCounter,
+		SelectionEntropyGauge, SelectionEntropyNormGauge, SelectionGiniGauge, SelectionHHIGauge, SelectionTop1Gauge,
+		SelectionGiniHistogram, SelectionWinnerPHistogram, WinConcentrationHHIGauge, WinTopShareGauge, WinnerRankCounter,
+		RewardEmissionBudgetGauge, RewardEmissionRemainingGauge, RewardEmissionScaledCounter)
 	http.Handle("/metrics", promhttp.Handler())
 	// 별도 HTTP 서버 (블로킹하지 않도록 상위에서 고루틴으로 호출 권장)
 	return http.ListenAndServe(addr, nil)
@@ -40,7 +40,7 @@ func InitRewardProducer() *kafka.Writer {
 	RewardProducer = &kafka.Writer{
 		Addr:     kafka.TCP(config.KafkaBrokers...),
 		Topic:    config.TopicResultVMemberReward,
-		Balancer: &kafka.LeastBytes{},
+		Balancer: &kafka.Hash{}, // key(풀노드 ID) 기준 파티션: 같은 풀노드의 라운드 결과 순서 유지
 	}
 	return RewardProducer
 }
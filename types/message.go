@@ -7,6 +7,7 @@ type ConnectRequest struct {
 	Password  string `json:"password"`
 	PublicKey string `json:"public_key"`
 	Address   string `json:"address"`
+	Location  string `json:"location,omitempty"` // 설치 위치 주소 (예: "대구광역시 동구 ...") → 권역 산출
 }
 
 // 투표자 수 최신화 관련 함수 users.go
@@ -67,13 +68,18 @@ type AccountRequest struct {
 type VMemberRequestMessage struct {
 	FullnodeID string   `json:"fullnode_id"` // 요청 보낸 풀노드 ID
 	Validators []string `json:"validators"`
+	Expected   []string `json:"expected,omitempty"` // 이번 라운드 기대 검증자 집합 (미참여 = expected - validators)
 	Timestamp  string   `json:"timestamp"`
 }
 
 // 풀노드에게 보상금을 전송하는 메세지
 type MemberRewardOutputMessage struct {
-	SenderID string             `json:"sender_id"` // 메시지 송신자 ID
-	Rewards  map[string]float64 `json:"rewards"`   // 주소 -> 보상금
+	SenderID      string             `json:"sender_id"`      // 메시지 송신자 ID (요청한 풀노드 ID)
+	Rewards       map[string]float64 `json:"rewards"`        // 주소 -> 보상금
+	RoundID       int64              `json:"round_id"`       // reward_round.round_id
+	PolicyVersion string             `json:"policy_version"` // 적용된 보상 정책 해시
+	BaseReward    float64            `json:"base_reward"`
+	Timestamp     string             `json:"timestamp"` // 요청 메시지의 timestamp
 }
 
 type TxHashRequest struct {